@@ -0,0 +1,112 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+// Basis is a snapshot of the statuses of all variables in the basis
+// of a problem along with the names of rows and columns. It may be
+// used to warm start the simplex method on another (e.g. slightly
+// perturbed) problem. As in the rest of the package the slices are
+// 1-based: element 0 is unused.
+type Basis struct {
+	RowNames []string  // row names ("" if a row has no name)
+	ColNames []string  // column names ("" if a column has no name)
+	RowStats []VarStat // statuses of auxiliary variables (rows)
+	ColStats []VarStat // statuses of structural variables (columns)
+}
+
+// Basis returns the current basis of the problem.
+func (p *Prob) Basis() *Basis {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	m, n := p.NumRows(), p.NumCols()
+	b := &Basis{
+		RowNames: make([]string, m+1),
+		ColNames: make([]string, n+1),
+		RowStats: p.RowStats(),
+		ColStats: p.ColStats(),
+	}
+	for i := 1; i <= m; i++ {
+		b.RowNames[i] = p.RowName(i)
+	}
+	for j := 1; j <= n; j++ {
+		b.ColNames[j] = p.ColName(j)
+	}
+	return b
+}
+
+// names maps names to indices (of their first occurrences).
+func names(a []string) map[string]int {
+	m := make(map[string]int, len(a))
+	for i := len(a) - 1; i >= 1; i-- {
+		if a[i] != "" {
+			m[a[i]] = i
+		}
+	}
+	return m
+}
+
+// SetBasis sets the statuses of the variables of the problem from
+// basis b (which may come from another problem). Rows and columns are
+// matched by name or, if they have no name or the name is not present
+// in b, by index provided that b has the same number of rows and
+// columns as the problem. Rows which can not be matched become basic
+// and columns which can not be matched become non-basic, so that the
+// basis remains valid when rows or columns are added. Non-basic
+// statuses are adjusted by GLPK to the current bounds types (see
+// SetRowStat). SetBasis returns glpk.EBADB (with the statuses set) if
+// the number of basic variables differs from the number of rows
+// (e.g. because some rows were removed), in which case a valid basis
+// may be constructed with StdBasis, AdvBasis or CpxBasis instead.
+func (p *Prob) SetBasis(b *Basis) error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	m, n := p.NumRows(), p.NumCols()
+	sameSize := len(b.RowStats) == m+1 && len(b.ColStats) == n+1
+	rows, cols := names(b.RowNames), names(b.ColNames)
+	nbas := 0
+	for i := 1; i <= m; i++ {
+		stat := BS
+		if k, ok := rows[p.RowName(i)]; ok && k < len(b.RowStats) {
+			stat = b.RowStats[k]
+		} else if sameSize {
+			stat = b.RowStats[i]
+		}
+		if stat == BS {
+			nbas++
+		}
+		p.SetRowStat(i, stat)
+	}
+	for j := 1; j <= n; j++ {
+		stat := NL
+		if k, ok := cols[p.ColName(j)]; ok && k < len(b.ColStats) {
+			stat = b.ColStats[k]
+		} else if sameSize {
+			stat = b.ColStats[j]
+		}
+		if stat == BS {
+			nbas++
+		}
+		p.SetColStat(j, stat)
+	}
+	if nbas != m {
+		return EBADB
+	}
+	return nil
+}
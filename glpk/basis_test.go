@@ -0,0 +1,121 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestStdAdvCpxBasis(t *testing.T) {
+	for _, f := range []func(*Prob){(*Prob).StdBasis, (*Prob).AdvBasis, (*Prob).CpxBasis} {
+		lp := sampleProb()
+		lp.SetTermWriter(ioutil.Discard)
+		f(lp)
+		if err := lp.WarmUp(); err != nil {
+			t.Errorf("WarmUp error: %v", err)
+		}
+		if err := lp.Simplex(nil); err != nil {
+			t.Errorf("Simplex error: %v", err)
+		}
+		CheckClose(t, lp.ObjVal(), 733.33333333333333)
+		lp.Delete()
+	}
+}
+
+func TestSetStat(t *testing.T) {
+	lp := sampleProb()
+	defer lp.Delete()
+	lp.StdBasis()
+	// the optimal basis: x0, x1 and r basic (see TestBasicSolution)
+	lp.SetRowStat(1, NU)
+	lp.SetRowStat(2, NU)
+	lp.SetColStat(1, BS)
+	lp.SetColStat(2, BS)
+	if err := lp.WarmUp(); err != nil {
+		t.Fatalf("WarmUp error: %v", err)
+	}
+	if lp.Status() != OPT {
+		t.Errorf("Expected optimal basis")
+	}
+	CheckClose(t, lp.ObjVal(), 733.33333333333333)
+	// fixed row gets NS although NL was requested
+	lp.SetRowBnds(1, FX, 100, 100)
+	lp.SetRowStat(1, NL)
+	if s := lp.RowStat(1); s != NS {
+		t.Errorf("Expected NS but got %d", s)
+	}
+}
+
+func TestSetBasis(t *testing.T) {
+	lp := sampleProb()
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	b := lp.Basis()
+	lp.Delete()
+	if len(b.RowStats) != 4 || b.RowNames[1] != "p" || b.ColStats[3] != NL {
+		t.Fatalf("Unexpected basis: %#v", b)
+	}
+
+	// same structure, perturbed bounds
+	lp = sampleProb()
+	lp.SetRowBnds(2, UP, 0, 601)
+	if err := lp.SetBasis(b); err != nil {
+		t.Fatalf("SetBasis error: %v", err)
+	}
+	if err := lp.WarmUp(); err != nil {
+		t.Fatalf("WarmUp error: %v", err)
+	}
+	if lp.Status() != OPT {
+		t.Errorf("Expected optimal basis")
+	}
+	lp.Delete()
+
+	// rows reordered and a new (non-binding) row added
+	lp = sampleProb()
+	lp.AddRows(2)
+	lp.SetRowName(4, "s")
+	lp.SetRowBnds(4, UP, 0, 1000)
+	lp.SetMatRow(4, []int32{0, 1}, []float64{0, 1})
+	lp.SetRowName(5, "p2")
+	lp.SetRowBnds(5, UP, 0, 100)
+	lp.SetMatRow(5, []int32{0, 1, 2, 3}, []float64{0, 1, 1, 1})
+	lp.DelRows([]int{1})
+	lp.SetRowName(4, "p")
+	if err := lp.SetBasis(b); err != nil {
+		t.Fatalf("SetBasis error: %v", err)
+	}
+	if s := lp.RowStat(3); s != BS {
+		t.Errorf("Expected new row to be basic but got %d", s)
+	}
+	if err := lp.WarmUp(); err != nil {
+		t.Fatalf("WarmUp error: %v", err)
+	}
+	if lp.Status() != OPT {
+		t.Errorf("Expected optimal basis")
+	}
+	CheckClose(t, lp.ObjVal(), 733.33333333333333)
+
+	// removed non-basic row
+	lp.DelRows([]int{4})
+	if err := lp.SetBasis(b); err != EBADB {
+		t.Errorf("Expected EBADB but got %v", err)
+	}
+	lp.Delete()
+}
@@ -0,0 +1,73 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "runtime/cgo"
+
+// #include <glpk.h>
+// #include "guard.h"
+// #include "tree.h"
+import "C"
+
+// iosState is the state of the branch-and-cut callback during a
+// single Prob.Intopt call. It is passed to C as a cgo.Handle so that
+// concurrent calls (each with its own callback) do not interfere.
+type iosState struct {
+	cb       func(t *Tree)
+	prob     *Prob
+	borrowed *Prob // presolved problem returned by Tree.GetProb
+	w        termWriter
+	panicked bool        // whether cb panicked
+	panicVal interface{} // value cb panicked with
+}
+
+// setCallback sets the branch-and-cut callback of parm to call s.cb.
+// The returned function should be called after glp_intopt returns.
+func (s *iosState) setCallback(parm *C.glp_iocp) func() {
+	h := cgo.NewHandle(s)
+	C.iocp_set_cb(parm, C.uintptr_t(h))
+	return h.Delete
+}
+
+//export goIosCallback
+func goIosCallback(t *C.glp_tree, h C.uintptr_t) {
+	s := cgo.Handle(h).Value().(*iosState)
+	if s.panicked {
+		// a panic can not propagate through C code so it is
+		// raised again when glp_intopt returns
+		C.glp_ios_terminate(t)
+		return
+	}
+	tree := &Tree{t: t, s: s}
+	defer func() {
+		tree.t = nil
+		if s.borrowed != nil {
+			// the presolved problem may be freed by glp_intopt
+			// once the callback returns
+			s.borrowed.p.p = nil
+			s.borrowed = nil
+		}
+		if v := recover(); v != nil {
+			s.panicked, s.panicVal = true, v
+			if C.guard_failed() == 0 {
+				C.glp_ios_terminate(t)
+			}
+		}
+	}()
+	s.cb(tree)
+}
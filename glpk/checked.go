@@ -0,0 +1,685 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ErrDeleted is returned by CheckedProb methods called on a deleted
+// (or invalidated, see FatalError) problem.
+var ErrDeleted = errors.New("glpk: problem is deleted")
+
+// IndexError is returned by CheckedProb methods for an out of range
+// or duplicate row or column index. Getters of Prob and Graph panic
+// with it for an out of range index.
+type IndexError struct {
+	Op    string // method, e.g. "SetRowBnds"
+	Kind  string // "row", "column" or "element" (of the constraint matrix)
+	Index int    // the invalid index
+	Max   int    // valid indices are in range [1, Max]
+	Dup   bool   // whether the index is duplicate (rather than out of range)
+}
+
+func (e *IndexError) Error() string {
+	if e.Dup {
+		return "glpk: " + e.Op + ": duplicate " + e.Kind + " index " + strconv.Itoa(e.Index)
+	}
+	return fmt.Sprintf("glpk: %s: %s index %d out of range [1, %d]", e.Op, e.Kind, e.Index, e.Max)
+}
+
+// BoundsError is returned by CheckedProb methods for invalid bounds:
+// an unknown bounds type, NaN or infinite value of a bound used by
+// the type, or lb > ub for a double-bounded variable.
+type BoundsError struct {
+	Op     string // method, e.g. "SetRowBnds"
+	Kind   string // "row" or "column"
+	Index  int
+	Type   BndsType
+	LB, UB float64
+}
+
+func (e *BoundsError) Error() string {
+	return fmt.Sprintf("glpk: %s: invalid bounds of %s %d: type %d, lb %g, ub %g", e.Op, e.Kind, e.Index, e.Type, e.LB, e.UB)
+}
+
+// ValueError is returned by CheckedProb methods for other invalid
+// arguments such as NaN or infinite coefficients.
+type ValueError struct {
+	Op    string // method, e.g. "SetObjCoef"
+	What  string // description of the argument
+	Index int    // its index (if applicable)
+	Value float64
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("glpk: %s: invalid %s %g", e.Op, e.What, e.Value)
+}
+
+// NameError is returned by CheckedProb methods for a symbolic name
+// which GLPK does not accept: one longer than 255 bytes or containing
+// a control character.
+type NameError struct {
+	Op   string // method, e.g. "SetRowName"
+	What string // description of the name, e.g. "row name"
+	Name string // the invalid name
+}
+
+func (e *NameError) Error() string {
+	if len(e.Name) > maxNameLen {
+		return fmt.Sprintf("glpk: %s: %s longer than %d bytes", e.Op, e.What, maxNameLen)
+	}
+	return fmt.Sprintf("glpk: %s: %s %q contains a control character", e.Op, e.What, e.Name)
+}
+
+// CheckedProb is a view of a problem whose methods validate their
+// arguments (and that the problem was not deleted) and return an
+// error instead of panicking. Note that only the arguments are
+// validated, e.g. CheckedProb.SetMatRow still replaces the row of the
+// problem. Use Prob.Checked() to obtain it.
+//
+// CheckedProb covers the methods of Prob which take a row or column
+// index, a name or other data which GLPK validates, and the solvers.
+// Methods without such arguments (e.g. NumRows, ObjVal or the bulk
+// getters such as ColPrims) can not fail on a valid problem and, like
+// the context-aware solvers (e.g. SimplexContext), are available only
+// through Prob().
+type CheckedProb struct {
+	p *Prob
+}
+
+// Checked returns a checked view of the problem (see CheckedProb).
+func (p *Prob) Checked() *CheckedProb {
+	return &CheckedProb{p}
+}
+
+// Prob returns the underlying problem.
+func (c *CheckedProb) Prob() *Prob {
+	return c.p
+}
+
+// maxNameLen is the maximal length of symbolic names in GLPK.
+const maxNameLen = 255
+
+// checkName rejects names for which glp_set_*_name would fail: too
+// long or containing a control character (iscntrl in the C locale).
+func checkName(op, what, name string) error {
+	if len(name) > maxNameLen {
+		return &NameError{op, what, name}
+	}
+	for i := 0; i < len(name); i++ {
+		if name[i] < 0x20 || name[i] == 0x7f {
+			return &NameError{op, what, name}
+		}
+	}
+	return nil
+}
+
+func checkIndex(op, kind string, i, n int) error {
+	if i < 1 || i > n {
+		return &IndexError{Op: op, Kind: kind, Index: i, Max: n}
+	}
+	return nil
+}
+
+func (c *CheckedProb) checkRow(op string, i int) error {
+	if c.p.p.invalid() {
+		return ErrDeleted
+	}
+	return checkIndex(op, "row", i, c.p.NumRows())
+}
+
+func (c *CheckedProb) checkCol(op string, j int) error {
+	if c.p.p.invalid() {
+		return ErrDeleted
+	}
+	return checkIndex(op, "column", j, c.p.NumCols())
+}
+
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+func checkBnds(op, kind string, i int, type_ BndsType, lb, ub float64) error {
+	ok := true
+	switch type_ {
+	case FR:
+	case LO, FX:
+		ok = finite(lb)
+	case UP:
+		ok = finite(ub)
+	case DB:
+		ok = finite(lb) && finite(ub) && lb <= ub
+	default:
+		ok = false
+	}
+	if !ok {
+		return &BoundsError{op, kind, i, type_, lb, ub}
+	}
+	return nil
+}
+
+// checkVec checks that ind[1:] contains distinct indices in range
+// [1, n] and that val[1:] contains finite values.
+func checkVec(op, kind string, ind []int32, val []float64, n int) error {
+	if len(ind) != len(val) {
+		return fmt.Errorf("glpk: %s: len(ind) = %d and len(val) = %d should be equal", op, len(ind), len(val))
+	}
+	if len(ind) == 0 {
+		return fmt.Errorf("glpk: %s: ind and val should have an unused element at index 0", op)
+	}
+	seen := make(map[int32]bool, len(ind))
+	for k := 1; k < len(ind); k++ {
+		if err := checkIndex(op, kind, int(ind[k]), n); err != nil {
+			return err
+		}
+		if seen[ind[k]] {
+			return &IndexError{Op: op, Kind: kind, Index: int(ind[k]), Max: n, Dup: true}
+		}
+		seen[ind[k]] = true
+		if !finite(val[k]) {
+			return &ValueError{op, "matrix element", k, val[k]}
+		}
+	}
+	return nil
+}
+
+// SetProbName sets (changes) the problem name.
+func (c *CheckedProb) SetProbName(name string) error {
+	if c.p.p.invalid() {
+		return ErrDeleted
+	}
+	if err := checkName("SetProbName", "problem name", name); err != nil {
+		return err
+	}
+	c.p.SetProbName(name)
+	return nil
+}
+
+// SetObjName sets (changes) objective function name.
+func (c *CheckedProb) SetObjName(name string) error {
+	if c.p.p.invalid() {
+		return ErrDeleted
+	}
+	if err := checkName("SetObjName", "objective name", name); err != nil {
+		return err
+	}
+	c.p.SetObjName(name)
+	return nil
+}
+
+// SetObjDir sets optimization direction (either glpk.MAX or
+// glpk.MIN).
+func (c *CheckedProb) SetObjDir(dir ObjDir) error {
+	if c.p.p.invalid() {
+		return ErrDeleted
+	}
+	if dir != MIN && dir != MAX {
+		return &ValueError{"SetObjDir", "optimization direction", 0, float64(dir)}
+	}
+	c.p.SetObjDir(dir)
+	return nil
+}
+
+// AddRows adds nrs rows (constraints) and returns the (1-based) index
+// of the first of them. nrs must be positive.
+func (c *CheckedProb) AddRows(nrs int) (int, error) {
+	if c.p.p.invalid() {
+		return 0, ErrDeleted
+	}
+	if nrs < 1 {
+		return 0, &ValueError{"AddRows", "number of rows", 0, float64(nrs)}
+	}
+	return c.p.AddRows(nrs), nil
+}
+
+// AddCols adds ncs columns (variables) and returns the (1-based)
+// index of the first of them. ncs must be positive.
+func (c *CheckedProb) AddCols(ncs int) (int, error) {
+	if c.p.p.invalid() {
+		return 0, ErrDeleted
+	}
+	if ncs < 1 {
+		return 0, &ValueError{"AddCols", "number of columns", 0, float64(ncs)}
+	}
+	return c.p.AddCols(ncs), nil
+}
+
+// SetRowName sets (changes) the name of i-th row.
+func (c *CheckedProb) SetRowName(i int, name string) error {
+	if err := c.checkRow("SetRowName", i); err != nil {
+		return err
+	}
+	if err := checkName("SetRowName", "row name", name); err != nil {
+		return err
+	}
+	c.p.SetRowName(i, name)
+	return nil
+}
+
+// SetColName sets (changes) the name of j-th column.
+func (c *CheckedProb) SetColName(j int, name string) error {
+	if err := c.checkCol("SetColName", j); err != nil {
+		return err
+	}
+	if err := checkName("SetColName", "column name", name); err != nil {
+		return err
+	}
+	c.p.SetColName(j, name)
+	return nil
+}
+
+// SetRowBnds sets row bounds (see Prob.SetRowBnds). Bounds used by
+// the given type should be finite and for glpk.DB lb <= ub.
+func (c *CheckedProb) SetRowBnds(i int, type_ BndsType, lb float64, ub float64) error {
+	if err := c.checkRow("SetRowBnds", i); err != nil {
+		return err
+	}
+	if err := checkBnds("SetRowBnds", "row", i, type_, lb, ub); err != nil {
+		return err
+	}
+	c.p.SetRowBnds(i, type_, lb, ub)
+	return nil
+}
+
+// SetColBnds sets column bounds (see Prob.SetColBnds). Bounds used by
+// the given type should be finite and for glpk.DB lb <= ub.
+func (c *CheckedProb) SetColBnds(j int, type_ BndsType, lb float64, ub float64) error {
+	if err := c.checkCol("SetColBnds", j); err != nil {
+		return err
+	}
+	if err := checkBnds("SetColBnds", "column", j, type_, lb, ub); err != nil {
+		return err
+	}
+	c.p.SetColBnds(j, type_, lb, ub)
+	return nil
+}
+
+// SetObjCoef sets objective function coefficient of j-th column (or
+// the constant term if j is 0). The coefficient should be finite.
+func (c *CheckedProb) SetObjCoef(j int, coef float64) error {
+	if j != 0 {
+		if err := c.checkCol("SetObjCoef", j); err != nil {
+			return err
+		}
+	} else if c.p.p.invalid() {
+		return ErrDeleted
+	}
+	if !finite(coef) {
+		return &ValueError{"SetObjCoef", "objective coefficient", j, coef}
+	}
+	c.p.SetObjCoef(j, coef)
+	return nil
+}
+
+// SetMatRow replaces i-th row of the constraint matrix (see
+// Prob.SetMatRow). Column indices should be distinct and in range
+// and values should be finite.
+func (c *CheckedProb) SetMatRow(i int, ind []int32, val []float64) error {
+	if err := c.checkRow("SetMatRow", i); err != nil {
+		return err
+	}
+	if err := checkVec("SetMatRow", "column", ind, val, c.p.NumCols()); err != nil {
+		return err
+	}
+	c.p.SetMatRow(i, ind, val)
+	return nil
+}
+
+// SetMatCol replaces j-th column of the constraint matrix (see
+// Prob.SetMatCol). Row indices should be distinct and in range and
+// values should be finite.
+func (c *CheckedProb) SetMatCol(j int, ind []int32, val []float64) error {
+	if err := c.checkCol("SetMatCol", j); err != nil {
+		return err
+	}
+	if err := checkVec("SetMatCol", "row", ind, val, c.p.NumRows()); err != nil {
+		return err
+	}
+	c.p.SetMatCol(j, ind, val)
+	return nil
+}
+
+// LoadMatrix replaces the whole constraint matrix (see
+// Prob.LoadMatrix). Row and column indices should be in range, there
+// should be no duplicate (row, column) pairs, and values should be
+// finite. A duplicate is reported as an *IndexError of kind
+// "element" with the index of the second occurrence.
+func (c *CheckedProb) LoadMatrix(ia, ja []int32, ar []float64) error {
+	if c.p.p.invalid() {
+		return ErrDeleted
+	}
+	if len(ia) != len(ja) || len(ia) != len(ar) {
+		return fmt.Errorf("glpk: LoadMatrix: len(ia) = %d, len(ja) = %d and len(ar) = %d should be equal", len(ia), len(ja), len(ar))
+	}
+	if len(ia) == 0 {
+		return errors.New("glpk: LoadMatrix: ia, ja and ar should have an unused element at index 0")
+	}
+	m, n := c.p.NumRows(), c.p.NumCols()
+	type pos struct{ i, j int32 }
+	seen := make(map[pos]bool, len(ia))
+	for k := 1; k < len(ia); k++ {
+		if err := checkIndex("LoadMatrix", "row", int(ia[k]), m); err != nil {
+			return err
+		}
+		if err := checkIndex("LoadMatrix", "column", int(ja[k]), n); err != nil {
+			return err
+		}
+		if seen[pos{ia[k], ja[k]}] {
+			return &IndexError{Op: "LoadMatrix", Kind: "element", Index: k, Max: len(ia) - 1, Dup: true}
+		}
+		seen[pos{ia[k], ja[k]}] = true
+		if !finite(ar[k]) {
+			return &ValueError{"LoadMatrix", "matrix element", k, ar[k]}
+		}
+	}
+	c.p.LoadMatrix(ia, ja, ar)
+	return nil
+}
+
+// SetColKind sets (changes) the kind of j-th column (glpk.CV,
+// glpk.IV or glpk.BV).
+func (c *CheckedProb) SetColKind(j int, kind VarType) error {
+	if err := c.checkCol("SetColKind", j); err != nil {
+		return err
+	}
+	if kind != CV && kind != IV && kind != BV {
+		return &ValueError{"SetColKind", "column kind", j, float64(kind)}
+	}
+	c.p.SetColKind(j, kind)
+	return nil
+}
+
+// DelRows deletes rows (see Prob.DelRows).
+func (c *CheckedProb) DelRows(rows []int) (mapping []int, err error) {
+	if c.p.p.invalid() {
+		return nil, ErrDeleted
+	}
+	return c.p.DelRows(rows)
+}
+
+// DelCols deletes columns (see Prob.DelCols).
+func (c *CheckedProb) DelCols(cols []int) (mapping []int, err error) {
+	if c.p.p.invalid() {
+		return nil, ErrDeleted
+	}
+	return c.p.DelCols(cols)
+}
+
+// RowName returns the name of i-th row.
+func (c *CheckedProb) RowName(i int) (string, error) {
+	if err := c.checkRow("RowName", i); err != nil {
+		return "", err
+	}
+	return c.p.RowName(i), nil
+}
+
+// ColName returns the name of j-th column.
+func (c *CheckedProb) ColName(j int) (string, error) {
+	if err := c.checkCol("ColName", j); err != nil {
+		return "", err
+	}
+	return c.p.ColName(j), nil
+}
+
+// MatRow returns nonzero elements of i-th row (see Prob.MatRow).
+func (c *CheckedProb) MatRow(i int) (ind []int32, val []float64, err error) {
+	if err := c.checkRow("MatRow", i); err != nil {
+		return nil, nil, err
+	}
+	ind, val = c.p.MatRow(i)
+	return ind, val, nil
+}
+
+// MatCol returns nonzero elements of j-th column (see Prob.MatCol).
+func (c *CheckedProb) MatCol(j int) (ind []int32, val []float64, err error) {
+	if err := c.checkCol("MatCol", j); err != nil {
+		return nil, nil, err
+	}
+	ind, val = c.p.MatCol(j)
+	return ind, val, nil
+}
+
+// FindRow returns the index of the row with the given name (see
+// Prob.FindRow).
+func (c *CheckedProb) FindRow(name string) (int, bool, error) {
+	if c.p.p.invalid() {
+		return 0, false, ErrDeleted
+	}
+	i, ok := c.p.FindRow(name)
+	return i, ok, nil
+}
+
+// FindCol returns the index of the column with the given name (see
+// Prob.FindCol).
+func (c *CheckedProb) FindCol(name string) (int, bool, error) {
+	if c.p.p.invalid() {
+		return 0, false, ErrDeleted
+	}
+	j, ok := c.p.FindCol(name)
+	return j, ok, nil
+}
+
+// RowType returns the type of i-th row.
+func (c *CheckedProb) RowType(i int) (BndsType, error) {
+	if err := c.checkRow("RowType", i); err != nil {
+		return 0, err
+	}
+	return c.p.RowType(i), nil
+}
+
+// RowLB returns the lower bound of i-th row.
+func (c *CheckedProb) RowLB(i int) (float64, error) {
+	if err := c.checkRow("RowLB", i); err != nil {
+		return 0, err
+	}
+	return c.p.RowLB(i), nil
+}
+
+// RowUB returns the upper bound of i-th row.
+func (c *CheckedProb) RowUB(i int) (float64, error) {
+	if err := c.checkRow("RowUB", i); err != nil {
+		return 0, err
+	}
+	return c.p.RowUB(i), nil
+}
+
+// ColType returns the type of j-th column.
+func (c *CheckedProb) ColType(j int) (BndsType, error) {
+	if err := c.checkCol("ColType", j); err != nil {
+		return 0, err
+	}
+	return c.p.ColType(j), nil
+}
+
+// ColLB returns the lower bound of j-th column.
+func (c *CheckedProb) ColLB(j int) (float64, error) {
+	if err := c.checkCol("ColLB", j); err != nil {
+		return 0, err
+	}
+	return c.p.ColLB(j), nil
+}
+
+// ColUB returns the upper bound of j-th column.
+func (c *CheckedProb) ColUB(j int) (float64, error) {
+	if err := c.checkCol("ColUB", j); err != nil {
+		return 0, err
+	}
+	return c.p.ColUB(j), nil
+}
+
+// ObjCoef returns the objective function coefficient of j-th column
+// (or the constant term if j is 0).
+func (c *CheckedProb) ObjCoef(j int) (float64, error) {
+	if j != 0 {
+		if err := c.checkCol("ObjCoef", j); err != nil {
+			return 0, err
+		}
+	} else if c.p.p.invalid() {
+		return 0, ErrDeleted
+	}
+	return c.p.ObjCoef(j), nil
+}
+
+// ColKind returns the kind of j-th column.
+func (c *CheckedProb) ColKind(j int) (VarType, error) {
+	if err := c.checkCol("ColKind", j); err != nil {
+		return 0, err
+	}
+	return c.p.ColKind(j), nil
+}
+
+// RowStat returns the status of i-th row in the basic solution.
+func (c *CheckedProb) RowStat(i int) (VarStat, error) {
+	if err := c.checkRow("RowStat", i); err != nil {
+		return 0, err
+	}
+	return c.p.RowStat(i), nil
+}
+
+// RowPrim returns the primal value of i-th row in the basic solution.
+func (c *CheckedProb) RowPrim(i int) (float64, error) {
+	if err := c.checkRow("RowPrim", i); err != nil {
+		return 0, err
+	}
+	return c.p.RowPrim(i), nil
+}
+
+// RowDual returns the dual value of i-th row in the basic solution.
+func (c *CheckedProb) RowDual(i int) (float64, error) {
+	if err := c.checkRow("RowDual", i); err != nil {
+		return 0, err
+	}
+	return c.p.RowDual(i), nil
+}
+
+// ColStat returns the status of j-th column in the basic solution.
+func (c *CheckedProb) ColStat(j int) (VarStat, error) {
+	if err := c.checkCol("ColStat", j); err != nil {
+		return 0, err
+	}
+	return c.p.ColStat(j), nil
+}
+
+// ColPrim returns the primal value of j-th column in the basic
+// solution.
+func (c *CheckedProb) ColPrim(j int) (float64, error) {
+	if err := c.checkCol("ColPrim", j); err != nil {
+		return 0, err
+	}
+	return c.p.ColPrim(j), nil
+}
+
+// ColDual returns the dual value of j-th column in the basic
+// solution.
+func (c *CheckedProb) ColDual(j int) (float64, error) {
+	if err := c.checkCol("ColDual", j); err != nil {
+		return 0, err
+	}
+	return c.p.ColDual(j), nil
+}
+
+// IptRowPrim returns the primal value of i-th row in the
+// interior-point solution.
+func (c *CheckedProb) IptRowPrim(i int) (float64, error) {
+	if err := c.checkRow("IptRowPrim", i); err != nil {
+		return 0, err
+	}
+	return c.p.IptRowPrim(i), nil
+}
+
+// IptRowDual returns the dual value of i-th row in the
+// interior-point solution.
+func (c *CheckedProb) IptRowDual(i int) (float64, error) {
+	if err := c.checkRow("IptRowDual", i); err != nil {
+		return 0, err
+	}
+	return c.p.IptRowDual(i), nil
+}
+
+// IptColPrim returns the primal value of j-th column in the
+// interior-point solution.
+func (c *CheckedProb) IptColPrim(j int) (float64, error) {
+	if err := c.checkCol("IptColPrim", j); err != nil {
+		return 0, err
+	}
+	return c.p.IptColPrim(j), nil
+}
+
+// IptColDual returns the dual value of j-th column in the
+// interior-point solution.
+func (c *CheckedProb) IptColDual(j int) (float64, error) {
+	if err := c.checkCol("IptColDual", j); err != nil {
+		return 0, err
+	}
+	return c.p.IptColDual(j), nil
+}
+
+// MipRowVal returns the value of i-th row in the MIP solution.
+func (c *CheckedProb) MipRowVal(i int) (float64, error) {
+	if err := c.checkRow("MipRowVal", i); err != nil {
+		return 0, err
+	}
+	return c.p.MipRowVal(i), nil
+}
+
+// MipColVal returns the value of j-th column in the MIP solution.
+func (c *CheckedProb) MipColVal(j int) (float64, error) {
+	if err := c.checkCol("MipColVal", j); err != nil {
+		return 0, err
+	}
+	return c.p.MipColVal(j), nil
+}
+
+// Simplex solves the LP with the simplex method (see Prob.Simplex).
+func (c *CheckedProb) Simplex(parm *Smcp) error {
+	if c.p.p.invalid() {
+		return ErrDeleted
+	}
+	return c.p.Simplex(parm)
+}
+
+// Exact solves the LP with the simplex method using exact arithmetic
+// (see Prob.Exact).
+func (c *CheckedProb) Exact(parm *Smcp) error {
+	if c.p.p.invalid() {
+		return ErrDeleted
+	}
+	return c.p.Exact(parm)
+}
+
+// Interior solves the LP with the interior-point method (see
+// Prob.Interior).
+func (c *CheckedProb) Interior(parm *Iptcp) error {
+	if c.p.p.invalid() {
+		return ErrDeleted
+	}
+	return c.p.Interior(parm)
+}
+
+// Intopt solves the MIP with the branch-and-cut method (see
+// Prob.Intopt).
+func (c *CheckedProb) Intopt(parm *Iocp) error {
+	if c.p.p.invalid() {
+		return ErrDeleted
+	}
+	return c.p.Intopt(parm)
+}
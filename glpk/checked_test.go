@@ -0,0 +1,159 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCheckedErrors(t *testing.T) {
+	lp := sampleProb()
+	c := lp.Checked()
+	tests := []struct {
+		name string
+		err  error
+		want interface{}
+	}{
+		{"row index", c.SetRowBnds(4, UP, 0, 1), &IndexError{Op: "SetRowBnds", Kind: "row", Index: 4, Max: 3}},
+		{"column index", c.SetColName(0, "x"), &IndexError{Op: "SetColName", Kind: "column", Index: 0, Max: 3}},
+		{"DB bounds", c.SetColBnds(1, DB, 2, 1), &BoundsError{"SetColBnds", "column", 1, DB, 2, 1}},
+		{"NaN bound", c.SetRowBnds(1, LO, math.NaN(), 0), &BoundsError{}},
+		{"bounds type", c.SetRowBnds(1, BndsType(100), 0, 0), &BoundsError{}},
+		{"Inf coef", c.SetObjCoef(1, math.Inf(1)), &ValueError{}},
+		{"mat row index", c.SetMatRow(1, []int32{0, 4}, []float64{0, 1}), &IndexError{}},
+		{"mat row dup", c.SetMatRow(1, []int32{0, 2, 2}, []float64{0, 1, 1}), &IndexError{Dup: true}},
+		{"mat row NaN", c.SetMatRow(1, []int32{0, 1}, []float64{0, math.NaN()}), &ValueError{}},
+		{"mat col NaN", c.SetMatCol(1, []int32{0, 1}, []float64{0, math.NaN()}), &ValueError{}},
+		{"load dup", c.LoadMatrix([]int32{0, 1, 1}, []int32{0, 2, 2}, []float64{0, 1, 1}), &IndexError{Op: "LoadMatrix", Kind: "element", Index: 2, Max: 2, Dup: true}},
+		{"AddRows", func() error { _, err := c.AddRows(0); return err }(), &ValueError{}},
+		{"control char", c.SetRowName(1, "a\nb"), &NameError{"SetRowName", "row name", "a\nb"}},
+		{"DEL char", c.SetObjName("x\x7fy"), &NameError{"SetObjName", "objective name", "x\x7fy"}},
+		{"long name", c.SetColName(1, strings.Repeat("x", 256)), &NameError{}},
+	}
+	for _, tt := range tests {
+		switch want := tt.want.(type) {
+		case *IndexError:
+			e, ok := tt.err.(*IndexError)
+			if !ok {
+				t.Errorf("%s: expected *IndexError but got %#v", tt.name, tt.err)
+			} else if (want.Op != "" && *e != *want) || e.Dup != want.Dup {
+				t.Errorf("%s: expected %#v but got %#v", tt.name, want, e)
+			}
+		case *BoundsError:
+			e, ok := tt.err.(*BoundsError)
+			if !ok {
+				t.Errorf("%s: expected *BoundsError but got %#v", tt.name, tt.err)
+			} else if want.Op != "" && *e != *want {
+				t.Errorf("%s: expected %#v but got %#v", tt.name, want, e)
+			}
+		case *ValueError:
+			if _, ok := tt.err.(*ValueError); !ok {
+				t.Errorf("%s: expected *ValueError but got %#v", tt.name, tt.err)
+			}
+		case *NameError:
+			e, ok := tt.err.(*NameError)
+			if !ok {
+				t.Errorf("%s: expected *NameError but got %#v", tt.name, tt.err)
+			} else if want.Op != "" && *e != *want {
+				t.Errorf("%s: expected %#v but got %#v", tt.name, want, e)
+			}
+		}
+	}
+	if ind, _ := lp.MatRow(1); len(ind) != 4 {
+		t.Errorf("rejected SetMatRow changed the problem")
+	}
+	if lp.RowName(1) != "p" || lp.ObjName() != "Z" {
+		t.Errorf("rejected SetRowName or SetObjName changed the problem")
+	}
+
+	lp.Delete()
+	if err := c.SetObjCoef(1, 1); err != ErrDeleted {
+		t.Errorf("expected ErrDeleted but got %v", err)
+	}
+	if _, err := c.AddCols(1); err != ErrDeleted {
+		t.Errorf("expected ErrDeleted but got %v", err)
+	}
+}
+
+func TestCheckedValid(t *testing.T) {
+	lp := sampleProb()
+	defer lp.Delete()
+	c := lp.Checked()
+	if err := c.SetRowBnds(3, DB, 0, 300); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetMatRow(3, []int32{0, 1, 3}, []float64{0, 2, 6}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetObjCoef(0, 5); err != nil {
+		t.Fatal(err)
+	}
+	ind, val, err := c.MatRow(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ind) != 3 || len(val) != 3 {
+		t.Errorf("expected 2 nonzero elements (and the unused element 0) but got %v %v", ind, val)
+	}
+	CheckClose(t, lp.RowUB(3), 300)
+	CheckClose(t, lp.ObjCoef(0), 5)
+}
+
+func TestCheckedGetters(t *testing.T) {
+	lp := sampleProb()
+	c := lp.Checked()
+	if err := c.Simplex(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.RowLB(4); !isIndexError(err, "RowLB", "row", 4) {
+		t.Errorf("expected *IndexError but got %#v", err)
+	}
+	if _, err := c.ColPrim(0); !isIndexError(err, "ColPrim", "column", 0) {
+		t.Errorf("expected *IndexError but got %#v", err)
+	}
+	if _, err := c.MipColVal(4); !isIndexError(err, "MipColVal", "column", 4) {
+		t.Errorf("expected *IndexError but got %#v", err)
+	}
+	if v, err := c.ColPrim(1); err != nil || v != lp.ColPrim(1) {
+		t.Errorf("got %v, %v but expected %v, nil", v, err, lp.ColPrim(1))
+	}
+	if v, err := c.ObjCoef(1); err != nil || v != 10 {
+		t.Errorf("got %v, %v but expected 10, nil", v, err)
+	}
+	if j, ok, err := c.FindCol("x1"); err != nil || !ok || j != 2 {
+		t.Errorf("got %v, %v, %v but expected 2, true, nil", j, ok, err)
+	}
+
+	lp.Delete()
+	if _, err := c.RowType(1); err != ErrDeleted {
+		t.Errorf("expected ErrDeleted but got %v", err)
+	}
+	if _, _, err := c.FindRow("p"); err != ErrDeleted {
+		t.Errorf("expected ErrDeleted but got %v", err)
+	}
+	if err := c.Intopt(nil); err != ErrDeleted {
+		t.Errorf("expected ErrDeleted but got %v", err)
+	}
+}
+
+func isIndexError(err error, op, kind string, i int) bool {
+	e, ok := err.(*IndexError)
+	return ok && e.Op == op && e.Kind == kind && e.Index == i
+}
@@ -0,0 +1,219 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"time"
+)
+
+// #include <glpk.h>
+import "C"
+
+// ContextError is returned by the solver variants accepting a
+// context.Context (such as Prob.SimplexContext) when the solver was
+// stopped because the context was canceled or its deadline passed.
+// The solution found so far (if any) is available as usual.
+type ContextError struct {
+	Err    error   // ctx.Err() (context.Canceled or context.DeadlineExceeded)
+	Status SolStat // status of the (partial) solution
+}
+
+func (e *ContextError) Error() string {
+	return "glpk: solver stopped: " + e.Err.Error()
+}
+
+// Unwrap returns e.Err so that errors.Is(err, context.Canceled) works.
+func (e *ContextError) Unwrap() error {
+	return e.Err
+}
+
+// lpChunk is the number of simplex iterations after which the
+// cancellation of the context is checked.
+var lpChunk = 200
+
+// noLimit is the GLPK value of unlimited it_lim and tm_lim.
+const noLimit = math.MaxInt32
+
+// ctxErr returns the context error or, if the deadline passed (but
+// ctx.Err() does not yet report it), context.DeadlineExceeded.
+func ctxErr(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d, ok := ctx.Deadline(); ok && !time.Now().Before(d) {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+// timeLimit returns tm_lim (in milliseconds) limited by the remaining
+// time (if ctx has a deadline) and whether the deadline was the limit.
+func timeLimit(ctx context.Context, tmLim int) (int, bool) {
+	d, ok := ctx.Deadline()
+	if !ok {
+		return tmLim, false
+	}
+	ms := time.Until(d) / time.Millisecond
+	if ms < 1 {
+		ms = 1
+	}
+	if ms < time.Duration(tmLim) {
+		return int(ms), true
+	}
+	return tmLim, false
+}
+
+// SimplexContext is like Simplex but it stops when ctx is canceled or
+// its deadline passes, returning a *ContextError then. The solver is
+// run in chunks of iterations, resumed from the last basis, so that
+// cancellation takes effect promptly. The deadline is also enforced
+// through the time limit of the solver. Note that if the LP presolver
+// is used (Smcp.SetPresolve) only the deadline is enforced during the
+// solution. Only the first chunk is run with the message level of
+// parm; later chunks are run with at most glpk.MSG_ON so that the
+// header of the solver is not repeated (thus messages printed only
+// at glpk.MSG_ALL, such as the final status, are omitted if the
+// solution took more than one chunk).
+func (p *Prob) SimplexContext(ctx context.Context, parm *Smcp) error {
+	return p.simplexContext(ctx, parm, p.Simplex)
+}
+
+// ExactContext is like Exact but it stops when ctx is canceled or its
+// deadline passes, returning a *ContextError then (see
+// SimplexContext).
+func (p *Prob) ExactContext(ctx context.Context, parm *Smcp) error {
+	return p.simplexContext(ctx, parm, p.Exact)
+}
+
+func (p *Prob) simplexContext(ctx context.Context, parm *Smcp, solve func(*Smcp) error) error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if err := ctxErr(ctx); err != nil {
+		return &ContextError{err, p.Status()}
+	}
+	var smcp Smcp
+	if parm != nil {
+		smcp = *parm
+	} else {
+		C.glp_init_smcp(&smcp.smcp)
+	}
+	itLim, tmLim := int(smcp.smcp.it_lim), int(smcp.smcp.tm_lim)
+	chunked := smcp.smcp.presolve == C.GLP_OFF
+	start := time.Now()
+	for its := 0; ; its += lpChunk {
+		// remaining user limits
+		it, tm := itLim, tmLim
+		if it != noLimit {
+			it -= its
+		}
+		if tm != noLimit {
+			tm -= int(time.Since(start) / time.Millisecond)
+			if tm < 0 {
+				tm = 0
+			}
+		}
+		ourIt := chunked && it > lpChunk
+		if ourIt {
+			it = lpChunk
+		}
+		tm, ourTm := timeLimit(ctx, tm)
+		smcp.smcp.it_lim, smcp.smcp.tm_lim = C.int(it), C.int(tm)
+		err := solve(&smcp)
+		switch {
+		case err == EITLIM && ourIt:
+			if err := ctxErr(ctx); err != nil {
+				return &ContextError{err, p.Status()}
+			}
+			// only progress lines for the resumed solution
+			if smcp.smcp.msg_lev > C.GLP_MSG_ON {
+				smcp.smcp.msg_lev = C.GLP_MSG_ON
+			}
+			continue
+		case err == ETMLIM && ourTm:
+			return &ContextError{context.DeadlineExceeded, p.Status()}
+		}
+		return err
+	}
+}
+
+// InteriorContext is like Interior but it returns a *ContextError if
+// ctx is canceled or its deadline passes. Note that the interior-point
+// solver has no time limit and it can not be interrupted so the
+// context is only checked before and after solving.
+func (p *Prob) InteriorContext(ctx context.Context, parm *Iptcp) error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if err := ctxErr(ctx); err != nil {
+		return &ContextError{err, p.IptStatus()}
+	}
+	err := p.Interior(parm)
+	if err := ctxErr(ctx); err != nil {
+		return &ContextError{err, p.IptStatus()}
+	}
+	return err
+}
+
+// IntoptContext is like Intopt but it stops when ctx is canceled or
+// its deadline passes, returning a *ContextError then. Cancellation
+// is checked in the branch-and-cut callback (see Iocp.SetCallback, a
+// callback set by the user is still called) and the deadline is also
+// enforced through the time limit of the solver.
+func (p *Prob) IntoptContext(ctx context.Context, parm *Iocp) error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if err := ctxErr(ctx); err != nil {
+		return &ContextError{err, p.MipStatus()}
+	}
+	// iocp is a copy of parm which is kept alive (as iocp may refer
+	// to memory owned by parm)
+	var iocp Iocp
+	if parm != nil {
+		iocp = *parm
+		defer runtime.KeepAlive(parm)
+	} else {
+		C.glp_init_iocp(&iocp.iocp)
+	}
+	tm, ourTm := timeLimit(ctx, int(iocp.iocp.tm_lim))
+	iocp.iocp.tm_lim = C.int(tm)
+	cb := iocp.cb
+	canceled := false
+	iocp.cb = func(t *Tree) {
+		if ctx.Err() != nil {
+			canceled = true
+			t.Terminate()
+			return
+		}
+		if cb != nil {
+			cb(t)
+		}
+	}
+	err := p.Intopt(&iocp)
+	switch {
+	case err == ESTOP && canceled:
+		return &ContextError{ctx.Err(), p.MipStatus()}
+	case err == ETMLIM && ourTm:
+		return &ContextError{context.DeadlineExceeded, p.MipStatus()}
+	}
+	return err
+}
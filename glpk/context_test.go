@@ -0,0 +1,184 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowLP returns a dense random LP with n rows and n columns which
+// takes the simplex method a while to solve. As the solvers are
+// resumed by many cgo calls the caller should lock its OS thread (see
+// the package documentation).
+func slowLP(n int) *Prob {
+	lp := New()
+	lp.SetTermWriter(ioutil.Discard)
+	lp.SetObjDir(MAX)
+	lp.AddRows(n)
+	lp.AddCols(n)
+	r := rand.New(rand.NewSource(1))
+	ia := make([]int32, 1, n*n+1)
+	ja := make([]int32, 1, n*n+1)
+	ar := make([]float64, 1, n*n+1)
+	for i := 1; i <= n; i++ {
+		lp.SetRowBnds(i, UP, 0, 1000+r.Float64()*1000)
+		lp.SetColBnds(i, LO, 0, 0)
+		lp.SetObjCoef(i, 1+r.Float64()*100)
+		for j := 1; j <= n; j++ {
+			ia = append(ia, int32(i))
+			ja = append(ja, int32(j))
+			ar = append(ar, 1+r.Float64()*100)
+		}
+	}
+	lp.LoadMatrix(ia, ja, ar)
+	return lp
+}
+
+// slowMIP returns the problem 2 x1 + ... + 2 xn = n (for odd n and
+// binary x) which has no integer solution but it takes branch-and-bound
+// exponential time to prove it. The caller should lock its OS thread
+// (see slowLP).
+func slowMIP(n int) *Prob {
+	lp := New()
+	lp.SetTermWriter(ioutil.Discard)
+	lp.AddRows(1)
+	lp.SetRowBnds(1, FX, float64(n), float64(n))
+	lp.AddCols(n)
+	ind := []int32{0}
+	val := []float64{0}
+	for j := 1; j <= n; j++ {
+		lp.SetColKind(j, BV)
+		lp.SetObjCoef(j, 1)
+		ind = append(ind, int32(j))
+		val = append(val, 2)
+	}
+	lp.SetMatRow(1, ind, val)
+	if err := lp.Simplex(nil); err != nil {
+		panic(err)
+	}
+	return lp
+}
+
+func TestSimplexContextCancel(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	lp := slowLP(600)
+	defer lp.Delete()
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	err := lp.SimplexContext(ctx, nil)
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("SimplexContext returned after %v", d)
+	}
+	var e *ContextError
+	if !errors.As(err, &e) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected *ContextError wrapping context.Canceled but got %v", err)
+	}
+	if e.Status == OPT {
+		t.Errorf("canceled solution should not be optimal")
+	}
+
+	// resuming finds the optimum
+	if err := lp.SimplexContext(context.Background(), nil); err != nil {
+		t.Fatalf("SimplexContext error: %v", err)
+	}
+	if lp.Status() != OPT {
+		t.Errorf("expected optimal solution")
+	}
+}
+
+func TestSimplexContextSolved(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	lp := sampleProb()
+	defer lp.Delete()
+	defer func(n int) { lpChunk = n }(lpChunk)
+	lpChunk = 1 // resume after every iteration
+	var buf bytes.Buffer
+	lp.SetTermWriter(&buf)
+	if err := lp.SimplexContext(context.Background(), nil); err != nil {
+		t.Fatalf("SimplexContext error: %v", err)
+	}
+	CheckClose(t, lp.ObjVal(), 733.33333333333333)
+	if n := strings.Count(buf.String(), "GLPK Simplex Optimizer"); n != 1 {
+		t.Errorf("expected the solver header once but got it %d times: %s", n, buf.String())
+	}
+	if err := lp.ExactContext(context.Background(), nil); err != nil {
+		t.Fatalf("ExactContext error: %v", err)
+	}
+	CheckClose(t, lp.ObjVal(), 733.33333333333333)
+}
+
+func TestIntoptContextDeadline(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	lp := slowMIP(41)
+	defer lp.Delete()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := lp.IntoptContext(ctx, nil)
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("IntoptContext returned after %v", d)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded but got %v", err)
+	}
+}
+
+func TestIntoptContextCancel(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	lp := slowMIP(41)
+	defer lp.Delete()
+	ctx, cancel := context.WithCancel(context.Background())
+	iocp := NewIocp()
+	calls := 0
+	iocp.SetCallback(func(tree *Tree) {
+		if calls++; calls == 100 {
+			cancel()
+		}
+	})
+	err := lp.IntoptContext(ctx, iocp)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled but got %v", err)
+	}
+}
+
+func TestInteriorContextCanceled(t *testing.T) {
+	lp := sampleProb()
+	defer lp.Delete()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := lp.InteriorContext(ctx, nil)
+	if e, ok := err.(*ContextError); !ok || e.Err != context.Canceled {
+		t.Fatalf("expected *ContextError but got %v", err)
+	}
+	if err := lp.InteriorContext(context.Background(), nil); err != nil {
+		t.Fatalf("InteriorContext error: %v", err)
+	}
+}
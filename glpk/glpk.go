@@ -31,6 +31,24 @@
 // to contact me if there is some part of GLPK that you would like to
 // use and it is not yet covered by the glpk package.
 //
+// Invalid arguments (such as out of range row or column indices)
+// cause fatal errors in GLPK which would abort the program. Instead
+// they result in a panic with a *FatalError value or, for methods
+// which return an error (such as Simplex), in returning it. Getters
+// which take a row or column index check it themselves and panic with
+// an *IndexError instead (without a fatal error). After a fatal error
+// the GLPK environment of the thread on which it occurred has to be
+// freed so all problems, translators and graphs created on that
+// thread become invalid (as if they were deleted). Objects created on
+// other threads are not affected.
+//
+// GLPK keeps its environment (including memory of the objects) per
+// thread so an object should be used only on the OS thread which
+// created it: a goroutine using GLPK should call runtime.LockOSThread
+// (unless GLPK is used by a single goroutine only and fatal errors
+// are not expected). Objects garbage collected (or deleted) on another
+// thread are freed by the next call on their own thread.
+//
 // Package glpk is free software: you can redistribute it and/or modify it
 // under the terms of the GNU General Public License as published by
 // the Free Software Foundation, either version 3 of the License, or
@@ -38,6 +56,8 @@
 package glpk
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 	"runtime"
 	"unsafe"
@@ -46,8 +66,233 @@ import (
 // #cgo LDFLAGS: -lglpk
 // #include <glpk.h>
 // #include <stdlib.h>
+// #include "guard.h"
+//
+// // GET_ALL defines function name which stores get(P, k) in val[k]
+// // for k = 1..count(P) so that whole vectors may be obtained with a
+// // single cgo call.
+// #define GET_ALL(name, count, get, type) \
+// static void name(glp_prob *P, type val[]) { \
+//	int k, n = count(P); \
+//	for (k = 1; k <= n; k++) \
+//		val[k] = get(P, k); \
+// }
+//
+// GET_ALL(get_row_types, glp_get_num_rows, glp_get_row_type, int)
+// GET_ALL(get_row_lbs, glp_get_num_rows, glp_get_row_lb, double)
+// GET_ALL(get_row_ubs, glp_get_num_rows, glp_get_row_ub, double)
+// GET_ALL(get_col_types, glp_get_num_cols, glp_get_col_type, int)
+// GET_ALL(get_col_lbs, glp_get_num_cols, glp_get_col_lb, double)
+// GET_ALL(get_col_ubs, glp_get_num_cols, glp_get_col_ub, double)
+// GET_ALL(get_row_stats, glp_get_num_rows, glp_get_row_stat, int)
+// GET_ALL(get_row_prims, glp_get_num_rows, glp_get_row_prim, double)
+// GET_ALL(get_row_duals, glp_get_num_rows, glp_get_row_dual, double)
+// GET_ALL(get_col_stats, glp_get_num_cols, glp_get_col_stat, int)
+// GET_ALL(get_col_prims, glp_get_num_cols, glp_get_col_prim, double)
+// GET_ALL(get_col_duals, glp_get_num_cols, glp_get_col_dual, double)
+//
+// // glp_create_index does nothing if the name index already exists.
+// static int find_row(glp_prob *P, const char *name) {
+//	glp_create_index(P);
+//	return glp_find_row(P, name);
+// }
+//
+// static int find_col(glp_prob *P, const char *name) {
+//	glp_create_index(P);
+//	return glp_find_col(P, name);
+// }
+//
+// // create_prob creates a problem and stores the id of the GLPK
+// // environment it belongs to in env.
+// static glp_prob *create_prob(uint64_t *env) {
+//	*env = guard_env();
+//	return glp_create_prob();
+// }
+//
+// // Guarded wrappers (see guard.h) of GLPK routines which may fail
+// // with a fatal error on invalid arguments.
+// GUARDED_VOID(go_glp_set_prob_name, (gcall *c, glp_prob *P, const char *name), glp_set_prob_name(P, name))
+// GUARDED_VOID(go_glp_set_obj_name, (gcall *c, glp_prob *P, const char *name), glp_set_obj_name(P, name))
+// GUARDED_VOID(go_glp_set_obj_dir, (gcall *c, glp_prob *P, int dir), glp_set_obj_dir(P, dir))
+// GUARDED(int, go_glp_add_rows, (gcall *c, glp_prob *P, int nrs), glp_add_rows(P, nrs))
+// GUARDED(int, go_glp_add_cols, (gcall *c, glp_prob *P, int ncs), glp_add_cols(P, ncs))
+// GUARDED_VOID(go_glp_set_row_name, (gcall *c, glp_prob *P, int i, const char *name), glp_set_row_name(P, i, name))
+// GUARDED_VOID(go_glp_set_col_name, (gcall *c, glp_prob *P, int j, const char *name), glp_set_col_name(P, j, name))
+// GUARDED_VOID(go_glp_set_row_bnds, (gcall *c, glp_prob *P, int i, int type, double lb, double ub), glp_set_row_bnds(P, i, type, lb, ub))
+// GUARDED_VOID(go_glp_set_col_bnds, (gcall *c, glp_prob *P, int j, int type, double lb, double ub), glp_set_col_bnds(P, j, type, lb, ub))
+// GUARDED_VOID(go_glp_set_obj_coef, (gcall *c, glp_prob *P, int j, double coef), glp_set_obj_coef(P, j, coef))
+// GUARDED_VOID(go_glp_set_mat_row, (gcall *c, glp_prob *P, int i, int len, const int ind[], const double val[]), glp_set_mat_row(P, i, len, ind, val))
+// GUARDED_VOID(go_glp_set_mat_col, (gcall *c, glp_prob *P, int j, int len, const int ind[], const double val[]), glp_set_mat_col(P, j, len, ind, val))
+// GUARDED_VOID(go_glp_load_matrix, (gcall *c, glp_prob *P, int ne, const int ia[], const int ja[], const double ar[]), glp_load_matrix(P, ne, ia, ja, ar))
+// GUARDED(int, go_glp_check_dup, (gcall *c, int m, int n, int ne, const int ia[], const int ja[]), glp_check_dup(m, n, ne, ia, ja))
+// GUARDED_VOID(go_glp_del_rows, (gcall *c, glp_prob *P, int nrs, const int num[]), glp_del_rows(P, nrs, num))
+// GUARDED_VOID(go_glp_del_cols, (gcall *c, glp_prob *P, int ncs, const int num[]), glp_del_cols(P, ncs, num))
+// GUARDED_VOID(go_glp_copy_prob, (gcall *c, glp_prob *dest, glp_prob *prob, int names), glp_copy_prob(dest, prob, names))
+// GUARDED(int, go_find_row, (gcall *c, glp_prob *P, const char *name), find_row(P, name))
+// GUARDED(int, go_find_col, (gcall *c, glp_prob *P, const char *name), find_col(P, name))
+// GUARDED_VOID(go_glp_adv_basis, (gcall *c, glp_prob *P), glp_adv_basis(P, 0))
+// GUARDED_VOID(go_glp_cpx_basis, (gcall *c, glp_prob *P), glp_cpx_basis(P))
+// GUARDED(int, go_glp_warm_up, (gcall *c, glp_prob *P), glp_warm_up(P))
+// GUARDED(int, go_glp_simplex, (gcall *c, glp_prob *P, const glp_smcp *parm), glp_simplex(P, parm))
+// GUARDED(int, go_glp_exact, (gcall *c, glp_prob *P, const glp_smcp *parm), glp_exact(P, parm))
+// GUARDED(int, go_glp_interior, (gcall *c, glp_prob *P, const glp_iptcp *parm), glp_interior(P, parm))
+// GUARDED_VOID(go_glp_set_rii, (gcall *c, glp_prob *P, int i, double rii), glp_set_rii(P, i, rii))
+// GUARDED_VOID(go_glp_set_sjj, (gcall *c, glp_prob *P, int j, double sjj), glp_set_sjj(P, j, sjj))
+// GUARDED_VOID(go_glp_scale_prob, (gcall *c, glp_prob *P, int flags), glp_scale_prob(P, flags))
+// GUARDED_VOID(go_glp_set_row_stat, (gcall *c, glp_prob *P, int i, int stat), glp_set_row_stat(P, i, stat))
+// GUARDED_VOID(go_glp_set_col_stat, (gcall *c, glp_prob *P, int j, int stat), glp_set_col_stat(P, j, stat))
+// GUARDED_VOID(go_glp_set_col_kind, (gcall *c, glp_prob *P, int j, int kind), glp_set_col_kind(P, j, kind))
+// GUARDED(int, go_glp_intopt, (gcall *c, glp_prob *P, const glp_iocp *parm), glp_intopt(P, parm))
+//
+// // Fields of glp_smcp and glp_iocp which are not present in all
+// // supported GLPK versions are accessed through the following
+// // functions. They return 0 if the field is not available in the
+// // GLPK version used.
+//
+// static int smcp_set_excl(glp_smcp *p, int v) {
+// #if GLPK_AT_LEAST(4, 60)
+//	p->excl = v;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
+//
+// static int smcp_get_excl(const glp_smcp *p, int *v) {
+// #if GLPK_AT_LEAST(4, 60)
+//	*v = p->excl;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
+//
+// static int smcp_set_shift(glp_smcp *p, int v) {
+// #if GLPK_AT_LEAST(4, 60)
+//	p->shift = v;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
+//
+// static int smcp_get_shift(const glp_smcp *p, int *v) {
+// #if GLPK_AT_LEAST(4, 60)
+//	*v = p->shift;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
+//
+// static int smcp_set_aorn(glp_smcp *p, int v) {
+// #if GLPK_AT_LEAST(4, 65)
+//	p->aorn = v;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
+//
+// static int smcp_get_aorn(const glp_smcp *p, int *v) {
+// #if GLPK_AT_LEAST(4, 65)
+//	*v = p->aorn;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
+//
+// #ifndef GLP_USE_AT
+// #define GLP_USE_AT 1
+// #define GLP_USE_NT 2
+// #endif
+//
+// static int major_version(void) { return GLP_MAJOR_VERSION; }
+// static int minor_version(void) { return GLP_MINOR_VERSION; }
+//
+// static int iocp_set_ps_heur(glp_iocp *p, int v) {
+// #if GLPK_AT_LEAST(4, 50)
+//	p->ps_heur = v;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
+//
+// static int iocp_set_ps_tm_lim(glp_iocp *p, int v) {
+// #if GLPK_AT_LEAST(4, 50)
+//	p->ps_tm_lim = v;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
+//
+// static int iocp_set_use_sol(glp_iocp *p, int v) {
+// #if GLPK_AT_LEAST(4, 52)
+//	p->use_sol = v;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
+//
+// static int iocp_set_save_sol(glp_iocp *p, const char *v) {
+// #if GLPK_AT_LEAST(4, 52)
+//	p->save_sol = v;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
+//
+// static int iocp_set_alien(glp_iocp *p, int v) {
+// #if GLPK_AT_LEAST(4, 52)
+//	p->alien = v;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
+//
+// static int iocp_set_sr_heur(glp_iocp *p, int v) {
+// #if GLPK_AT_LEAST(4, 57)
+//	p->sr_heur = v;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
+//
+// static int iocp_set_flip(glp_iocp *p, int v) {
+// #if GLPK_AT_LEAST(4, 60)
+//	p->flip = v;
+//	return 1;
+// #else
+//	return 0;
+// #endif
+// }
 import "C"
 
+// ErrUnsupported is returned when setting a control parameter (or
+// calling a routine) which is not available in the GLPK version the
+// package was built against.
+var ErrUnsupported = errors.New("glpk: not supported by this GLPK version")
+
+// Version returns the version of the GLPK library in use (e.g.
+// "4.65").
+func Version() string {
+	return C.GoString(C.glp_version())
+}
+
+// HeaderVersion returns the version of GLPK headers the package was
+// built against. It determines which control parameters are
+// supported (see ErrUnsupported).
+func HeaderVersion() (major, minor int) {
+	return int(C.major_version()), int(C.minor_version())
+}
+
 // Objective function direction (maximization or minimization).
 type ObjDir int
 
@@ -79,8 +324,66 @@ const (
 	UNBND  = SolStat(C.GLP_UNBND)  // UNBND indicates that the problem has unbounded solution
 )
 
+// Solution type
+type SolType int
+
+const (
+	SOL = SolType(C.GLP_SOL) // SOL represents the basic solution
+	IPT = SolType(C.GLP_IPT) // IPT represents the interior-point solution
+	MIP = SolType(C.GLP_MIP) // MIP represents the mixed integer solution
+)
+
 type prob struct {
-	p *C.glp_prob
+	p        *C.glp_prob
+	env      uint64     // id of the GLPK environment p belongs to
+	w        termWriter // terminal output writer (see SetTermWriter)
+	borrowed bool       // whether p is owned by GLPK (see Tree.GetProb)
+	solving  bool       // whether Intopt with a callback is running on p
+}
+
+func newProb() *prob {
+	var env C.uint64_t
+	p := C.create_prob(&env)
+	return &prob{p: p, env: uint64(env)}
+}
+
+// invalid reports whether the problem was deleted (or freed along with
+// its GLPK environment after a fatal error).
+func (p *prob) invalid() bool {
+	return p.p == nil || envDead(p.env)
+}
+
+// free deletes the problem unless it was already freed.
+func (p *prob) free() {
+	if p.borrowed {
+		// p is owned by GLPK and w by the problem passed to Intopt
+		return
+	}
+	if p.p != nil && !envDead(p.env) {
+		C.guard_free(C.GUARD_PROB, unsafe.Pointer(p.p), C.uint64_t(p.env))
+	}
+	p.p = nil
+	p.w.release()
+	p.w = 0
+}
+
+// row returns i if it is a valid row index or panics with an
+// *IndexError (getters check indices instead of GLPK so that they
+// need not be guarded).
+func (p *prob) row(op string, i int) C.int {
+	if err := checkIndex(op, "row", i, int(C.glp_get_num_rows(p.p))); err != nil {
+		panic(err)
+	}
+	return C.int(i)
+}
+
+// col returns j if it is a valid column index or panics with an
+// *IndexError (see row).
+func (p *prob) col(op string, j int) C.int {
+	if err := checkIndex(op, "column", j, int(C.glp_get_num_cols(p.p))); err != nil {
+		panic(err)
+	}
+	return C.int(j)
 }
 
 // Prob represens optimization problem. Use glpk.New() to create a new problem.
@@ -89,15 +392,12 @@ type Prob struct {
 }
 
 func finalizeProb(p *prob) {
-	if p.p != nil {
-		C.glp_delete_prob(p.p)
-		p.p = nil
-	}
+	p.free()
 }
 
 // New creates a new optimization problem.
 func New() *Prob {
-	p := &prob{C.glp_create_prob()}
+	p := newProb()
 	runtime.SetFinalizer(p, finalizeProb)
 	return &Prob{p}
 }
@@ -106,112 +406,140 @@ func New() *Prob {
 // have no effect (It is save to do so). But calling any other method
 // on a deleted problem will panic. The problem will be deleted on
 // garbage collection but you can do this as soon as you no longer
-// need the optimization problem.
+// need the optimization problem. It panics if called from the
+// callback of Intopt() on the problem being solved.
 func (p *Prob) Delete() {
-	if p.p.p != nil {
-		C.glp_delete_prob(p.p.p)
-		p.p.p = nil
+	if p.p.solving {
+		panic("Prob deleted during Intopt")
 	}
+	p.p.free()
 }
 
 // Erase erases the problem. After erasing the problem is empty as if
-// it were created with glpk.New().
+// it were created with glpk.New(). It panics if called from the
+// callback of Intopt() on the problem being solved (or on the problem
+// returned by Tree.GetProb()).
 func (p *Prob) Erase() {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
+	if p.p.solving || p.p.borrowed {
+		panic("Prob erased during Intopt")
+	}
 	C.glp_erase_prob(p.p.p)
 }
 
 // SetProbName sets (changes) the problem name.
 func (p *Prob) SetProbName(name string) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	s := C.CString(name)
 	defer C.free(unsafe.Pointer(s))
-	C.glp_set_prob_name(p.p.p, s)
+	c := newCall(p.p.w)
+	C.go_glp_set_prob_name(&c.c, p.p.p, s)
+	c.done()
 }
 
 // SetObjName sets (changes) objective function name.
 func (p *Prob) SetObjName(name string) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	s := C.CString(name)
 	defer C.free(unsafe.Pointer(s))
-	C.glp_set_obj_name(p.p.p, s)
+	c := newCall(p.p.w)
+	C.go_glp_set_obj_name(&c.c, p.p.p, s)
+	c.done()
 }
 
 // SetObjDir sets optimization direction (either glpk.MAX for
 // maximization or glpk.MIN for minimization)
 func (p *Prob) SetObjDir(dir ObjDir) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
-	C.glp_set_obj_dir(p.p.p, C.int(dir))
+	c := newCall(p.p.w)
+	C.go_glp_set_obj_dir(&c.c, p.p.p, C.int(dir))
+	c.done()
 }
 
 // AddRows adds rows (constraints). Returns (1-based) index of the
 // first of the added rows.
 func (p *Prob) AddRows(nrs int) int {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
-	return int(C.glp_add_rows(p.p.p, C.int(nrs)))
+	c := newCall(p.p.w)
+	r := int(C.go_glp_add_rows(&c.c, p.p.p, C.int(nrs)))
+	c.done()
+	return r
 }
 
 // AddCols adds columns (variables). Returns (1-based) index of the
 // first of the added columns.
 func (p *Prob) AddCols(nrs int) int {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
-	return int(C.glp_add_cols(p.p.p, C.int(nrs)))
+	c := newCall(p.p.w)
+	r := int(C.go_glp_add_cols(&c.c, p.p.p, C.int(nrs)))
+	c.done()
+	return r
 }
 
 // SetRowName sets i-th row (constraint) name.
 func (p *Prob) SetRowName(i int, name string) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	s := C.CString(name)
 	defer C.free(unsafe.Pointer(s))
-	C.glp_set_row_name(p.p.p, C.int(i), s)
+	c := newCall(p.p.w)
+	C.go_glp_set_row_name(&c.c, p.p.p, C.int(i), s)
+	c.done()
 }
 
 // SetColName sets j-th column (variable) name.
 func (p *Prob) SetColName(j int, name string) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	s := C.CString(name)
 	defer C.free(unsafe.Pointer(s))
-	C.glp_set_col_name(p.p.p, C.int(j), s)
+	c := newCall(p.p.w)
+	C.go_glp_set_col_name(&c.c, p.p.p, C.int(j), s)
+	c.done()
 }
 
 // SetRowBnds sets row bounds
 func (p *Prob) SetRowBnds(i int, type_ BndsType, lb float64, ub float64) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
-	C.glp_set_row_bnds(p.p.p, C.int(i), C.int(type_), C.double(lb), C.double(ub))
+	c := newCall(p.p.w)
+	C.go_glp_set_row_bnds(&c.c, p.p.p, C.int(i), C.int(type_), C.double(lb), C.double(ub))
+	c.done()
 }
 
 // SetColBnds sets column bounds
 func (p *Prob) SetColBnds(j int, type_ BndsType, lb float64, ub float64) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
-	C.glp_set_col_bnds(p.p.p, C.int(j), C.int(type_), C.double(lb), C.double(ub))
+	c := newCall(p.p.w)
+	C.go_glp_set_col_bnds(&c.c, p.p.p, C.int(j), C.int(type_), C.double(lb), C.double(ub))
+	c.done()
 }
 
 // SetObjCoef sets objective function coefficient of j-th column.
 func (p *Prob) SetObjCoef(j int, coef float64) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
-	C.glp_set_obj_coef(p.p.p, C.int(j), C.double(coef))
+	c := newCall(p.p.w)
+	C.go_glp_set_obj_coef(&c.c, p.p.p, C.int(j), C.double(coef))
+	c.done()
 }
 
 // SetMatRow sets (replaces) i-th row. It sets
@@ -221,7 +549,7 @@ func (p *Prob) SetObjCoef(j int, coef float64) {
 // for j=1..len(ind). ind[0] and val[0] are ignored. Requires
 // len(ind) = len(val).
 func (p *Prob) SetMatRow(i int, ind []int32, val []float64) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	if len(ind) != len(val) {
@@ -229,7 +557,9 @@ func (p *Prob) SetMatRow(i int, ind []int32, val []float64) {
 	}
 	ind_ := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
 	val_ := (*reflect.SliceHeader)(unsafe.Pointer(&val))
-	C.glp_set_mat_row(p.p.p, C.int(i), C.int(len(ind)-1), (*C.int)(unsafe.Pointer(ind_.Data)), (*C.double)(unsafe.Pointer(val_.Data)))
+	c := newCall(p.p.w)
+	C.go_glp_set_mat_row(&c.c, p.p.p, C.int(i), C.int(len(ind)-1), (*C.int)(unsafe.Pointer(ind_.Data)), (*C.double)(unsafe.Pointer(val_.Data)))
+	c.done()
 }
 
 // SetMatCol sets (replaces) j-th column. It sets
@@ -239,7 +569,7 @@ func (p *Prob) SetMatRow(i int, ind []int32, val []float64) {
 // for i=1..len(ind). ind[0] and val[0] are ignored. Requires
 // len(ind) = len(val).
 func (p *Prob) SetMatCol(j int, ind []int32, val []float64) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	if len(ind) != len(val) {
@@ -247,7 +577,9 @@ func (p *Prob) SetMatCol(j int, ind []int32, val []float64) {
 	}
 	ind_ := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
 	val_ := (*reflect.SliceHeader)(unsafe.Pointer(&val))
-	C.glp_set_mat_col(p.p.p, C.int(j), C.int(len(ind)-1), (*C.int)(unsafe.Pointer(ind_.Data)), (*C.double)(unsafe.Pointer(val_.Data)))
+	c := newCall(p.p.w)
+	C.go_glp_set_mat_col(&c.c, p.p.p, C.int(j), C.int(len(ind)-1), (*C.int)(unsafe.Pointer(ind_.Data)), (*C.double)(unsafe.Pointer(val_.Data)))
+	c.done()
 }
 
 // LoadMatrix replaces all of the constraint matrix. It sets
@@ -257,7 +589,7 @@ func (p *Prob) SetMatCol(j int, ind []int32, val []float64) {
 // for i = 1..len(ia). ia[0], ja[0], and ar[0] are ignored. It
 // requiers len(ia)=len(ja)=len(ar).
 func (p *Prob) LoadMatrix(ia, ja []int32, ar []float64) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	if len(ia) != len(ja) || len(ia) != len(ar) {
@@ -266,33 +598,142 @@ func (p *Prob) LoadMatrix(ia, ja []int32, ar []float64) {
 	ia_ := (*reflect.SliceHeader)(unsafe.Pointer(&ia))
 	ja_ := (*reflect.SliceHeader)(unsafe.Pointer(&ja))
 	ar_ := (*reflect.SliceHeader)(unsafe.Pointer(&ar))
-	C.glp_load_matrix(p.p.p, C.int(len(ia)-1), (*C.int)(unsafe.Pointer(ia_.Data)), (*C.int)(unsafe.Pointer(ja_.Data)), (*C.double)(unsafe.Pointer(ar_.Data)))
+	c := newCall(p.p.w)
+	C.go_glp_load_matrix(&c.c, p.p.p, C.int(len(ia)-1), (*C.int)(unsafe.Pointer(ia_.Data)), (*C.int)(unsafe.Pointer(ja_.Data)), (*C.double)(unsafe.Pointer(ar_.Data)))
+	c.done()
+}
+
+// CheckDup checks that ia[k], ja[k] (k=1..len(ia)) are valid
+// (1-based) row and column indices of an m x n matrix and that there
+// are no duplicate elements. ia[0] and ja[0] are ignored. It requires
+// len(ia)=len(ja). CheckDup may be used to validate arguments of
+// SetMatRow(), SetMatCol() and LoadMatrix() as GLPK aborts the program
+// on invalid or duplicate indices.
+func CheckDup(m, n int, ia, ja []int32) error {
+	if len(ia) != len(ja) {
+		panic("len(ia) and len(ja) should be equal")
+	}
+	if m < 0 || n < 0 {
+		return fmt.Errorf("glpk: invalid matrix dimensions %d x %d", m, n)
+	}
+	if len(ia) <= 1 {
+		return nil
+	}
+	ia_ := (*reflect.SliceHeader)(unsafe.Pointer(&ia))
+	ja_ := (*reflect.SliceHeader)(unsafe.Pointer(&ja))
+	c := newCall(0)
+	k := int(C.go_glp_check_dup(&c.c, C.int(m), C.int(n), C.int(len(ia)-1), (*C.int)(unsafe.Pointer(ia_.Data)), (*C.int)(unsafe.Pointer(ja_.Data))))
+	if err := c.err(); err != nil {
+		return err
+	}
+	switch {
+	case k < 0:
+		return fmt.Errorf("glpk: element %d has index (%d, %d) out of range of %d x %d matrix", -k, ia[-k], ja[-k], m, n)
+	case k > 0:
+		return fmt.Errorf("glpk: element %d has duplicate index (%d, %d)", k, ia[k], ja[k])
+	}
+	return nil
+}
+
+// delIndices checks that num contains distinct indices in range
+// [1, n] and returns them in GLPK format (with unused num_[0]) along
+// with old-to-new index mapping which would result from deleting them.
+func delIndices(what string, num []int, n int) (num_ []int32, mapping []int, err error) {
+	mapping = make([]int, n+1)
+	num_ = make([]int32, len(num)+1)
+	for k, i := range num {
+		if i < 1 || i > n {
+			return nil, nil, fmt.Errorf("glpk: %s index %d out of range [1, %d]", what, i, n)
+		}
+		if mapping[i] < 0 {
+			return nil, nil, fmt.Errorf("glpk: duplicate %s index %d", what, i)
+		}
+		mapping[i] = -1
+		num_[k+1] = int32(i)
+	}
+	k := 0
+	for i := 1; i <= n; i++ {
+		if mapping[i] < 0 {
+			mapping[i] = 0
+		} else {
+			k++
+			mapping[i] = k
+		}
+	}
+	return num_, mapping, nil
+}
+
+// DelRows deletes rows (constraints) with (1-based) indices given in
+// rows (unlike in SetMatRow() there is no unused element at index
+// 0). The remaining rows are renumbered preserving their order. It
+// returns the mapping from old to new row indices: mapping[i] is the
+// new index of the row which had index i (or 0 if it was deleted),
+// and mapping[0] is unused. An error is returned (and no rows are
+// deleted) if rows contains duplicate or out of range indices.
+func (p *Prob) DelRows(rows []int) (mapping []int, err error) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	num, mapping, err := delIndices("row", rows, p.NumRows())
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > 0 {
+		num_ := (*reflect.SliceHeader)(unsafe.Pointer(&num))
+		c := newCall(p.p.w)
+		C.go_glp_del_rows(&c.c, p.p.p, C.int(len(rows)), (*C.int)(unsafe.Pointer(num_.Data)))
+		c.done()
+	}
+	return mapping, nil
 }
 
-// TODO:
-// glp_check_dup
-// glp_del_rows
+// DelCols deletes columns (variables) with (1-based) indices given in
+// cols (unlike in SetMatCol() there is no unused element at index
+// 0). The remaining columns are renumbered preserving their order. It
+// returns the mapping from old to new column indices: mapping[j] is
+// the new index of the column which had index j (or 0 if it was
+// deleted), and mapping[0] is unused. An error is returned (and no
+// columns are deleted) if cols contains duplicate or out of range
+// indices.
+func (p *Prob) DelCols(cols []int) (mapping []int, err error) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	num, mapping, err := delIndices("column", cols, p.NumCols())
+	if err != nil {
+		return nil, err
+	}
+	if len(cols) > 0 {
+		num_ := (*reflect.SliceHeader)(unsafe.Pointer(&num))
+		c := newCall(p.p.w)
+		C.go_glp_del_cols(&c.c, p.p.p, C.int(len(cols)), (*C.int)(unsafe.Pointer(num_.Data)))
+		c.done()
+	}
+	return mapping, nil
+}
 
 // Copy returns a copy of the given optimization problem. If name is
 // true also symbolic names are copies otherwise their not copied
 func (p *Prob) Copy(names bool) *Prob {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
-	q := &Prob{&prob{C.glp_create_prob()}}
+	q := &Prob{newProb()}
 	var names_ C.int
 	if names {
 		names_ = C.GLP_ON
 	} else {
 		names_ = C.GLP_OFF
 	}
-	C.glp_copy_prob(q.p.p, p.p.p, names_)
+	c := newCall(p.p.w)
+	C.go_glp_copy_prob(&c.c, q.p.p, p.p.p, names_)
+	c.done()
 	return q
 }
 
 // ProbName returns problem name.
 func (p *Prob) ProbName() string {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	return C.GoString(C.glp_get_prob_name(p.p.p))
@@ -300,7 +741,7 @@ func (p *Prob) ProbName() string {
 
 // ObjName returns objective name.
 func (p *Prob) ObjName() string {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	return C.GoString(C.glp_get_obj_name(p.p.p))
@@ -308,7 +749,7 @@ func (p *Prob) ObjName() string {
 
 // ObjDir returns optimization direction (either glpk.MAX or glpk.MIN).
 func (p *Prob) ObjDir() ObjDir {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	return ObjDir(C.glp_get_obj_dir(p.p.p))
@@ -316,7 +757,7 @@ func (p *Prob) ObjDir() ObjDir {
 
 // NumRows returns number of rows.
 func (p *Prob) NumRows() int {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	return int(C.glp_get_num_rows(p.p.p))
@@ -324,7 +765,7 @@ func (p *Prob) NumRows() int {
 
 // NumCols returns number of columns.
 func (p *Prob) NumCols() int {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	return int(C.glp_get_num_cols(p.p.p))
@@ -332,56 +773,195 @@ func (p *Prob) NumCols() int {
 
 // RowName returns row (constraint) name of i-th row.
 func (p *Prob) RowName(i int) string {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
-	return C.GoString(C.glp_get_row_name(p.p.p, C.int(i)))
+	return C.GoString(C.glp_get_row_name(p.p.p, p.p.row("RowName", i)))
 }
 
 // ColName returns column (variable) name of j-th column.
 func (p *Prob) ColName(j int) string {
-	if p.p.p == nil {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return C.GoString(C.glp_get_col_name(p.p.p, p.p.col("ColName", j)))
+}
+
+// RowType returns the type of i-th row (the type of the auxiliary
+// variable associated with the row).
+func (p *Prob) RowType(i int) BndsType {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return BndsType(C.glp_get_row_type(p.p.p, p.p.row("RowType", i)))
+}
+
+// RowLB returns the lower bound of i-th row. It returns -math.MaxFloat64
+// if the row has no lower bound.
+func (p *Prob) RowLB(i int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_row_lb(p.p.p, p.p.row("RowLB", i)))
+}
+
+// RowUB returns the upper bound of i-th row. It returns +math.MaxFloat64
+// if the row has no upper bound.
+func (p *Prob) RowUB(i int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_row_ub(p.p.p, p.p.row("RowUB", i)))
+}
+
+// ColType returns the type of j-th column (the type of the structural
+// variable associated with the column).
+func (p *Prob) ColType(j int) BndsType {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return BndsType(C.glp_get_col_type(p.p.p, p.p.col("ColType", j)))
+}
+
+// ColLB returns the lower bound of j-th column. It returns
+// -math.MaxFloat64 if the column has no lower bound.
+func (p *Prob) ColLB(j int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_col_lb(p.p.p, p.p.col("ColLB", j)))
+}
+
+// ColUB returns the upper bound of j-th column. It returns
+// +math.MaxFloat64 if the column has no upper bound.
+func (p *Prob) ColUB(j int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_col_ub(p.p.p, p.p.col("ColUB", j)))
+}
+
+func bndsTypes(buf []int32) []BndsType {
+	types := make([]BndsType, len(buf))
+	for i := 1; i < len(buf); i++ {
+		types[i] = BndsType(buf[i])
+	}
+	return types
+}
+
+// RowTypes returns types of all rows. types[1]..types[m] are the types
+// of rows 1..m where m is the number of rows (types[0] is unused).
+func (p *Prob) RowTypes() []BndsType {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	buf := make([]int32, p.NumRows()+1)
+	buf_ := (*reflect.SliceHeader)(unsafe.Pointer(&buf))
+	C.get_row_types(p.p.p, (*C.int)(unsafe.Pointer(buf_.Data)))
+	return bndsTypes(buf)
+}
+
+// RowLBs returns lower bounds of all rows. lb[1]..lb[m] are the lower
+// bounds of rows 1..m where m is the number of rows (lb[0] is unused).
+func (p *Prob) RowLBs() []float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	lb := make([]float64, p.NumRows()+1)
+	lb_ := (*reflect.SliceHeader)(unsafe.Pointer(&lb))
+	C.get_row_lbs(p.p.p, (*C.double)(unsafe.Pointer(lb_.Data)))
+	return lb
+}
+
+// RowUBs returns upper bounds of all rows. ub[1]..ub[m] are the upper
+// bounds of rows 1..m where m is the number of rows (ub[0] is unused).
+func (p *Prob) RowUBs() []float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	ub := make([]float64, p.NumRows()+1)
+	ub_ := (*reflect.SliceHeader)(unsafe.Pointer(&ub))
+	C.get_row_ubs(p.p.p, (*C.double)(unsafe.Pointer(ub_.Data)))
+	return ub
+}
+
+// ColTypes returns types of all columns. types[1]..types[n] are the
+// types of columns 1..n where n is the number of columns (types[0] is
+// unused).
+func (p *Prob) ColTypes() []BndsType {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	buf := make([]int32, p.NumCols()+1)
+	buf_ := (*reflect.SliceHeader)(unsafe.Pointer(&buf))
+	C.get_col_types(p.p.p, (*C.int)(unsafe.Pointer(buf_.Data)))
+	return bndsTypes(buf)
+}
+
+// ColLBs returns lower bounds of all columns. lb[1]..lb[n] are the
+// lower bounds of columns 1..n where n is the number of columns (lb[0]
+// is unused).
+func (p *Prob) ColLBs() []float64 {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
-	return C.GoString(C.glp_get_col_name(p.p.p, C.int(j)))
+	lb := make([]float64, p.NumCols()+1)
+	lb_ := (*reflect.SliceHeader)(unsafe.Pointer(&lb))
+	C.get_col_lbs(p.p.p, (*C.double)(unsafe.Pointer(lb_.Data)))
+	return lb
 }
 
-// TODO:
-// glp_get_row_type
-// glp_get_row_lb
-// glp_get_row_ub
-// glp_get_col_type
-// glp_get_col_lb
-// glp_get_col_ub
+// ColUBs returns upper bounds of all columns. ub[1]..ub[n] are the
+// upper bounds of columns 1..n where n is the number of columns (ub[0]
+// is unused).
+func (p *Prob) ColUBs() []float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	ub := make([]float64, p.NumCols()+1)
+	ub_ := (*reflect.SliceHeader)(unsafe.Pointer(&ub))
+	C.get_col_ubs(p.p.p, (*C.double)(unsafe.Pointer(ub_.Data)))
+	return ub
+}
 
 // ObjCoef returns objective function coefficient of j-th column.
 func (p *Prob) ObjCoef(j int) float64 {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
+	if j != 0 {
+		p.p.col("ObjCoef", j)
+	}
 	return float64(C.glp_get_obj_coef(p.p.p, C.int(j)))
 }
 
-// TODO:
-// glp_get_num_nz
+// NumNz returns the number of non-zero elements in the constraint
+// matrix.
+func (p *Prob) NumNz() int {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return int(C.glp_get_num_nz(p.p.p))
+}
 
 // MatRow returns nonzero elements of i-th row. ind[1]..ind[n] are
 // column numbers of the nonzero elements of the row, val[1]..val[n]
 // are their values, and n is the number of nonzero elements in the
 // row.
 func (p *Prob) MatRow(i int) (ind []int32, val []float64) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	if len(ind) != len(val) {
 		panic("len(ind) and len(val) should be equal")
 	}
-	length := C.glp_get_mat_row(p.p.p, C.int(i), nil, nil)
+	i_ := p.p.row("MatRow", i)
+	length := C.glp_get_mat_row(p.p.p, i_, nil, nil)
 	ind = make([]int32, length+1)
 	val = make([]float64, length+1)
 	ind_ := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
 	val_ := (*reflect.SliceHeader)(unsafe.Pointer(&val))
-	C.glp_get_mat_row(p.p.p, C.int(i), (*C.int)(unsafe.Pointer(ind_.Data)), (*C.double)(unsafe.Pointer(val_.Data)))
+	C.glp_get_mat_row(p.p.p, i_, (*C.int)(unsafe.Pointer(ind_.Data)), (*C.double)(unsafe.Pointer(val_.Data)))
 	return
 }
 
@@ -390,37 +970,213 @@ func (p *Prob) MatRow(i int) (ind []int32, val []float64) {
 // are their values, and n is the number of nonzero elements in the
 // column.
 func (p *Prob) MatCol(j int) (ind []int32, val []float64) {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
 	if len(ind) != len(val) {
 		panic("len(ind) and len(val) should be equal")
 	}
-	length := C.glp_get_mat_col(p.p.p, C.int(j), nil, nil)
+	j_ := p.p.col("MatCol", j)
+	length := C.glp_get_mat_col(p.p.p, j_, nil, nil)
 	ind = make([]int32, length+1)
 	val = make([]float64, length+1)
 	ind_ := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
 	val_ := (*reflect.SliceHeader)(unsafe.Pointer(&val))
-	C.glp_get_mat_col(p.p.p, C.int(j), (*C.int)(unsafe.Pointer(ind_.Data)), (*C.double)(unsafe.Pointer(val_.Data)))
+	C.glp_get_mat_col(p.p.p, j_, (*C.int)(unsafe.Pointer(ind_.Data)), (*C.double)(unsafe.Pointer(val_.Data)))
 	return
 }
 
-// TODO:
-// glp_create_index
-// glp_find_row
-// glp_find_col
-// glp_delete_index
-// glp_set_rii
-// glp_set_sjj
-// glp_get_rii
-// glp_get_sjj
-// glp_scale_prob
-// glp_unscale_prob
-// glp_set_row_stat
-// glp_set_col_stat
-// glp_std_basis
-// glp_adv_basis
-// glp_cpx_basis
+// FindRow returns the index of the row with the given name. The
+// second result is false if there is no such row. The name index used
+// for searching is created on the first call and it is maintained by
+// GLPK until DeleteIndex() is called.
+func (p *Prob) FindRow(name string) (int, bool) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	s := C.CString(name)
+	defer C.free(unsafe.Pointer(s))
+	c := newCall(p.p.w)
+	i := int(C.go_find_row(&c.c, p.p.p, s))
+	c.done()
+	return i, i != 0
+}
+
+// FindCol returns the index of the column with the given name. The
+// second result is false if there is no such column. The name index
+// used for searching is created on the first call and it is
+// maintained by GLPK until DeleteIndex() is called.
+func (p *Prob) FindCol(name string) (int, bool) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	s := C.CString(name)
+	defer C.free(unsafe.Pointer(s))
+	c := newCall(p.p.w)
+	j := int(C.go_find_col(&c.c, p.p.p, s))
+	c.done()
+	return j, j != 0
+}
+
+// DeleteIndex deletes the row and column name index created by
+// FindRow() or FindCol() to free memory used by it. It is safe to
+// call DeleteIndex if the index does not exist.
+func (p *Prob) DeleteIndex() {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	C.glp_delete_index(p.p.p)
+}
+
+// Scaling flags (may be combined with bitwise or)
+type ScaleFlag int
+
+const (
+	SF_GM   = ScaleFlag(C.GLP_SF_GM)   // perform geometric mean scaling
+	SF_EQ   = ScaleFlag(C.GLP_SF_EQ)   // perform equilibration scaling
+	SF_2N   = ScaleFlag(C.GLP_SF_2N)   // round scale factors to nearest power of two
+	SF_SKIP = ScaleFlag(C.GLP_SF_SKIP) // skip scaling, if the problem is well scaled
+	SF_AUTO = ScaleFlag(C.GLP_SF_AUTO) // choose scaling options automatically
+)
+
+// SetRii sets (changes) the scale factor for i-th row. The factor
+// should be positive.
+func (p *Prob) SetRii(i int, rii float64) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	c := newCall(p.p.w)
+	C.go_glp_set_rii(&c.c, p.p.p, C.int(i), C.double(rii))
+	c.done()
+}
+
+// SetSjj sets (changes) the scale factor for j-th column. The factor
+// should be positive.
+func (p *Prob) SetSjj(j int, sjj float64) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	c := newCall(p.p.w)
+	C.go_glp_set_sjj(&c.c, p.p.p, C.int(j), C.double(sjj))
+	c.done()
+}
+
+// Rii returns the current scale factor for i-th row.
+func (p *Prob) Rii(i int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_rii(p.p.p, p.p.row("Rii", i)))
+}
+
+// Sjj returns the current scale factor for j-th column.
+func (p *Prob) Sjj(j int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_sjj(p.p.p, p.p.col("Sjj", j)))
+}
+
+// ScaleProb performs automatic scaling of the problem data (i.e.,
+// computes the row and column scale factors) according to flags (a
+// combination of glpk.SF_GM, glpk.SF_EQ, glpk.SF_2N and glpk.SF_SKIP,
+// or glpk.SF_AUTO). Scaling affects only the internal representation
+// used by the simplex solver (which may help on badly scaled problems
+// failing with glpk.ESING or glpk.ECOND): the problem data and the
+// solution values remain unscaled. Prob.Simplex() does not scale the
+// problem by itself. See also UnscaleProb().
+func (p *Prob) ScaleProb(flags ScaleFlag) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	c := newCall(p.p.w)
+	C.go_glp_scale_prob(&c.c, p.p.p, C.int(flags))
+	c.done()
+}
+
+// UnscaleProb sets all the row and column scale factors to 1.
+func (p *Prob) UnscaleProb() {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	C.glp_unscale_prob(p.p.p)
+}
+
+// SetRowStat sets (changes) the status of the auxiliary variable
+// associated with i-th row in the current basis. For a non-basic
+// status the actual status is chosen by GLPK according to the type of
+// the row (e.g. glpk.NS for a fixed row).
+func (p *Prob) SetRowStat(i int, stat VarStat) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	c := newCall(p.p.w)
+	C.go_glp_set_row_stat(&c.c, p.p.p, C.int(i), C.int(stat))
+	c.done()
+}
+
+// SetColStat sets (changes) the status of the structural variable
+// associated with j-th column in the current basis (see SetRowStat).
+func (p *Prob) SetColStat(j int, stat VarStat) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	c := newCall(p.p.w)
+	C.go_glp_set_col_stat(&c.c, p.p.p, C.int(j), C.int(stat))
+	c.done()
+}
+
+// StdBasis constructs the trivial (standard) initial basis in which
+// all auxiliary variables are basic and all structural variables are
+// non-basic.
+func (p *Prob) StdBasis() {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	C.glp_std_basis(p.p.p)
+}
+
+// AdvBasis constructs an advanced initial basis trying to include as
+// many structural variables as possible so that the basis matrix is
+// triangular.
+func (p *Prob) AdvBasis() {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	c := newCall(p.p.w)
+	C.go_glp_adv_basis(&c.c, p.p.p)
+	c.done()
+}
+
+// CpxBasis constructs an initial basis with the algorithm proposed by
+// R. Bixby.
+func (p *Prob) CpxBasis() {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	c := newCall(p.p.w)
+	C.go_glp_cpx_basis(&c.c, p.p.p)
+	c.done()
+}
+
+// WarmUp "warms up" the current basis: it computes its factorization
+// (if needed) and the basic solution components (primal and dual
+// values and statuses) so that they are available without calling the
+// solver. It returns nil or an OptError (glpk.EBADB if the basis is
+// invalid, glpk.ESING if the basis matrix is singular, and glpk.ECOND
+// if it is ill-conditioned).
+func (p *Prob) WarmUp() error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	c := newCall(p.p.w)
+	ret := C.go_glp_warm_up(&c.c, p.p.p)
+	c.done()
+	if ret != 0 {
+		return OptError(ret)
+	}
+	return nil
+}
 
 // Optimization Error
 type OptError int
@@ -497,19 +1253,22 @@ func (r OptError) Error() string {
 // optimal solution) otherwise returns an error which is an instanse
 // of OptError.
 func (p *Prob) Simplex(parm *Smcp) error {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
-	var err OptError
+	var smcp *C.glp_smcp
 	if parm != nil {
-		err = OptError(C.glp_simplex(p.p.p, &parm.smcp))
-	} else {
-		err = OptError(C.glp_simplex(p.p.p, nil))
+		smcp = &parm.smcp
 	}
-	if err == 0 {
-		return nil
+	c := newCall(p.p.w)
+	ret := C.go_glp_simplex(&c.c, p.p.p, smcp)
+	if err := c.err(); err != nil {
+		return err
 	}
-	return err
+	if ret != 0 {
+		return OptError(ret)
+	}
+	return nil
 }
 
 // Exact solves LP with Simplex method using exact (rational)
@@ -518,19 +1277,22 @@ func (p *Prob) Simplex(parm *Smcp) error {
 // been solved (not necessarly finding optimal solution) otherwise
 // returns an error which is an instanse of OptError.
 func (p *Prob) Exact(parm *Smcp) error {
-	if p.p.p == nil {
+	if p.p.invalid() {
 		panic("Prob method called on a deleted problem")
 	}
-	var err OptError
+	var smcp *C.glp_smcp
 	if parm != nil {
-		err = OptError(C.glp_exact(p.p.p, &parm.smcp))
-	} else {
-		err = OptError(C.glp_exact(p.p.p, nil))
+		smcp = &parm.smcp
 	}
-	if err == 0 {
-		return nil
+	c := newCall(p.p.w)
+	ret := C.go_glp_exact(&c.c, p.p.p, smcp)
+	if err := c.err(); err != nil {
+		return err
+	}
+	if ret != 0 {
+		return OptError(ret)
 	}
-	return err
+	return nil
 }
 
 // Smcp represents simplex solver control parameters, a set of
@@ -638,53 +1400,956 @@ func (s *Smcp) SetRTest(r_test RTest) {
 	s.smcp.r_test = C.int(r_test)
 }
 
-// Status returns status of the basic solution.
-func (p *Prob) Status() SolStat {
-	if p.p.p == nil {
-		panic("Prob method called on a deleted problem")
-	}
-	return SolStat(C.glp_get_status(p.p.p))
+// MsgLev returns message level.
+func (s *Smcp) MsgLev() MsgLev {
+	return MsgLev(s.smcp.msg_lev)
 }
 
-// PrimStat returns status of the primal basic solution.
-func (p *Prob) PrimStat() SolStat {
-	if p.p.p == nil {
-		panic("Prob method called on a deleted problem")
-	}
-	return SolStat(C.glp_get_prim_stat(p.p.p))
+// Meth returns simplex method option.
+func (s *Smcp) Meth() Meth {
+	return Meth(s.smcp.meth)
 }
 
-// DualStat returns status of the dual basic solution.
-func (p *Prob) DualStat() SolStat {
-	if p.p.p == nil {
-		panic("Prob method called on a deleted problem")
-	}
-	return SolStat(C.glp_get_dual_stat(p.p.p))
+// Pricing returns pricing technique.
+func (s *Smcp) Pricing() Pricing {
+	return Pricing(s.smcp.pricing)
 }
 
-// ObjVal returns objective function value.
-func (p *Prob) ObjVal() float64 {
-	if p.p.p == nil {
-		panic("Prob method called on a deleted problem")
-	}
-	return float64(C.glp_get_obj_val(p.p.p))
+// RTest returns ratio test technique.
+func (s *Smcp) RTest() RTest {
+	return RTest(s.smcp.r_test)
 }
 
-// TODO:
-// glp_get_row_stat
-// glp_get_row_prim
-// glp_get_row_dual
-// glp_get_col_stat
+// SetTolBnd sets tolerance used to check if the basic solution is
+// primal feasible (default: 1e-7).
+func (s *Smcp) SetTolBnd(tol float64) {
+	s.smcp.tol_bnd = C.double(tol)
+}
 
-// ColPrim returns primal value of the variable associated with j-th
-// column.
-func (p *Prob) ColPrim(j int) float64 {
-	if p.p.p == nil {
-		panic("Prob method called on a deleted problem")
-	}
-	return float64(C.glp_get_col_prim(p.p.p, C.int(j)))
+// TolBnd returns tolerance used to check if the basic solution is
+// primal feasible.
+func (s *Smcp) TolBnd() float64 {
+	return float64(s.smcp.tol_bnd)
+}
+
+// SetTolDj sets tolerance used to check if the basic solution is dual
+// feasible (default: 1e-7).
+func (s *Smcp) SetTolDj(tol float64) {
+	s.smcp.tol_dj = C.double(tol)
+}
+
+// TolDj returns tolerance used to check if the basic solution is dual
+// feasible.
+func (s *Smcp) TolDj() float64 {
+	return float64(s.smcp.tol_dj)
+}
+
+// SetTolPiv sets tolerance used to choose eligible pivotal elements
+// of the simplex table (default: 1e-10).
+func (s *Smcp) SetTolPiv(tol float64) {
+	s.smcp.tol_piv = C.double(tol)
+}
+
+// TolPiv returns tolerance used to choose eligible pivotal elements of
+// the simplex table.
+func (s *Smcp) TolPiv() float64 {
+	return float64(s.smcp.tol_piv)
+}
+
+// SetObjLL sets lower limit of the objective function (default:
+// -math.MaxFloat64). If the objective function reaches this limit and
+// continues decreasing the solver stops the search. It is used only
+// in the dual simplex.
+func (s *Smcp) SetObjLL(v float64) {
+	s.smcp.obj_ll = C.double(v)
+}
+
+// ObjLL returns lower limit of the objective function.
+func (s *Smcp) ObjLL() float64 {
+	return float64(s.smcp.obj_ll)
+}
+
+// SetObjUL sets upper limit of the objective function (default:
+// math.MaxFloat64). If the objective function reaches this limit and
+// continues increasing the solver stops the search. It is used only
+// in the dual simplex.
+func (s *Smcp) SetObjUL(v float64) {
+	s.smcp.obj_ul = C.double(v)
+}
+
+// ObjUL returns upper limit of the objective function.
+func (s *Smcp) ObjUL() float64 {
+	return float64(s.smcp.obj_ul)
+}
+
+// SetItLim sets simplex iteration limit (default: math.MaxInt32,
+// i.e., no limit).
+func (s *Smcp) SetItLim(n int) {
+	s.smcp.it_lim = C.int(n)
+}
+
+// ItLim returns simplex iteration limit.
+func (s *Smcp) ItLim() int {
+	return int(s.smcp.it_lim)
+}
+
+// SetTmLim sets searching time limit in milliseconds (default:
+// math.MaxInt32, i.e., no limit).
+func (s *Smcp) SetTmLim(ms int) {
+	s.smcp.tm_lim = C.int(ms)
+}
+
+// TmLim returns searching time limit in milliseconds.
+func (s *Smcp) TmLim() int {
+	return int(s.smcp.tm_lim)
+}
+
+// SetOutFrq sets output frequency in iterations (default: 500). This
+// parameter specifies how frequently the solver sends information
+// about the solution process to the terminal.
+func (s *Smcp) SetOutFrq(n int) {
+	s.smcp.out_frq = C.int(n)
+}
+
+// OutFrq returns output frequency in iterations.
+func (s *Smcp) OutFrq() int {
+	return int(s.smcp.out_frq)
 }
 
-// TODO:
-// glp_get_col_dual
-// ...
+// SetOutDly sets output delay in milliseconds (default: 0). This
+// parameter specifies how long the solver should delay sending
+// information about the solution process to the terminal.
+func (s *Smcp) SetOutDly(ms int) {
+	s.smcp.out_dly = C.int(ms)
+}
+
+// OutDly returns output delay in milliseconds.
+func (s *Smcp) OutDly() int {
+	return int(s.smcp.out_dly)
+}
+
+// SetPresolve enables or disables using the LP presolver (default:
+// disabled).
+func (s *Smcp) SetPresolve(on bool) {
+	s.smcp.presolve = glpBool(on)
+}
+
+// Presolve returns whether the LP presolver is enabled.
+func (s *Smcp) Presolve() bool {
+	return s.smcp.presolve != C.GLP_OFF
+}
+
+// SetExcl enables or disables excluding fixed non-basic variables
+// from the working LP (default: enabled). It returns ErrUnsupported if
+// GLPK is older than 4.60.
+func (s *Smcp) SetExcl(on bool) error {
+	if C.smcp_set_excl(&s.smcp, glpBool(on)) == 0 {
+		return ErrUnsupported
+	}
+	return nil
+}
+
+// Excl returns whether fixed non-basic variables are excluded from
+// the working LP. It returns ErrUnsupported if GLPK is older than
+// 4.60.
+func (s *Smcp) Excl() (bool, error) {
+	var v C.int
+	if C.smcp_get_excl(&s.smcp, &v) == 0 {
+		return false, ErrUnsupported
+	}
+	return v != C.GLP_OFF, nil
+}
+
+// SetShift enables or disables shifting bounds of variables in the
+// working LP toward zero (default: enabled). It returns
+// ErrUnsupported if GLPK is older than 4.60.
+func (s *Smcp) SetShift(on bool) error {
+	if C.smcp_set_shift(&s.smcp, glpBool(on)) == 0 {
+		return ErrUnsupported
+	}
+	return nil
+}
+
+// Shift returns whether bounds of variables in the working LP are
+// shifted toward zero. It returns ErrUnsupported if GLPK is older
+// than 4.60.
+func (s *Smcp) Shift() (bool, error) {
+	var v C.int
+	if C.smcp_get_shift(&s.smcp, &v) == 0 {
+		return false, ErrUnsupported
+	}
+	return v != C.GLP_OFF, nil
+}
+
+// Simplex option of using the constraint matrix
+type Aorn int
+
+const (
+	USE_AT = Aorn(C.GLP_USE_AT) // use A matrix in row-wise format
+	USE_NT = Aorn(C.GLP_USE_NT) // use N matrix in row-wise format
+)
+
+// SetAorn sets which matrix format the simplex solver uses (default:
+// glpk.USE_NT). It returns ErrUnsupported if GLPK is older than 4.65.
+func (s *Smcp) SetAorn(aorn Aorn) error {
+	if C.smcp_set_aorn(&s.smcp, C.int(aorn)) == 0 {
+		return ErrUnsupported
+	}
+	return nil
+}
+
+// Aorn returns which matrix format the simplex solver uses. It
+// returns ErrUnsupported if GLPK is older than 4.65.
+func (s *Smcp) Aorn() (Aorn, error) {
+	var v C.int
+	if C.smcp_get_aorn(&s.smcp, &v) == 0 {
+		return 0, ErrUnsupported
+	}
+	return Aorn(v), nil
+}
+
+// String returns the effective parameters (using names of glp_smcp
+// fields), e.g. for logging. Parameters not supported by the GLPK
+// version are omitted.
+func (s *Smcp) String() string {
+	str := fmt.Sprintf("glp_smcp{msg_lev: %d, meth: %d, pricing: %d, r_test: %d, "+
+		"tol_bnd: %g, tol_dj: %g, tol_piv: %g, obj_ll: %g, obj_ul: %g, "+
+		"it_lim: %d, tm_lim: %d, out_frq: %d, out_dly: %d, presolve: %t",
+		s.MsgLev(), s.Meth(), s.Pricing(), s.RTest(),
+		s.TolBnd(), s.TolDj(), s.TolPiv(), s.ObjLL(), s.ObjUL(),
+		s.ItLim(), s.TmLim(), s.OutFrq(), s.OutDly(), s.Presolve())
+	if v, err := s.Excl(); err == nil {
+		str += fmt.Sprintf(", excl: %t", v)
+	}
+	if v, err := s.Shift(); err == nil {
+		str += fmt.Sprintf(", shift: %t", v)
+	}
+	if v, err := s.Aorn(); err == nil {
+		str += fmt.Sprintf(", aorn: %d", v)
+	}
+	return str + "}"
+}
+
+// Status returns status of the basic solution.
+func (p *Prob) Status() SolStat {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return SolStat(C.glp_get_status(p.p.p))
+}
+
+// PrimStat returns status of the primal basic solution.
+func (p *Prob) PrimStat() SolStat {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return SolStat(C.glp_get_prim_stat(p.p.p))
+}
+
+// DualStat returns status of the dual basic solution.
+func (p *Prob) DualStat() SolStat {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return SolStat(C.glp_get_dual_stat(p.p.p))
+}
+
+// ObjVal returns objective function value.
+func (p *Prob) ObjVal() float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_obj_val(p.p.p))
+}
+
+// Variable status in the basic solution
+type VarStat int
+
+const (
+	BS = VarStat(C.GLP_BS) // BS indicates a basic variable
+	NL = VarStat(C.GLP_NL) // NL indicates a non-basic variable on its lower bound
+	NU = VarStat(C.GLP_NU) // NU indicates a non-basic variable on its upper bound
+	NF = VarStat(C.GLP_NF) // NF indicates a non-basic free (unbounded) variable
+	NS = VarStat(C.GLP_NS) // NS indicates a non-basic fixed variable
+)
+
+func varStats(buf []int32) []VarStat {
+	stats := make([]VarStat, len(buf))
+	for i := 1; i < len(buf); i++ {
+		stats[i] = VarStat(buf[i])
+	}
+	return stats
+}
+
+// RowStat returns status of the auxiliary variable associated with
+// i-th row.
+func (p *Prob) RowStat(i int) VarStat {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return VarStat(C.glp_get_row_stat(p.p.p, p.p.row("RowStat", i)))
+}
+
+// RowPrim returns primal value of the auxiliary variable associated
+// with i-th row.
+func (p *Prob) RowPrim(i int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_row_prim(p.p.p, p.p.row("RowPrim", i)))
+}
+
+// RowDual returns dual value (i.e. reduced cost, also known as shadow
+// price of the constraint) of the auxiliary variable associated with
+// i-th row.
+func (p *Prob) RowDual(i int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_row_dual(p.p.p, p.p.row("RowDual", i)))
+}
+
+// ColStat returns status of the structural variable associated with
+// j-th column.
+func (p *Prob) ColStat(j int) VarStat {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return VarStat(C.glp_get_col_stat(p.p.p, p.p.col("ColStat", j)))
+}
+
+// ColPrim returns primal value of the variable associated with j-th
+// column.
+func (p *Prob) ColPrim(j int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_col_prim(p.p.p, p.p.col("ColPrim", j)))
+}
+
+// ColDual returns dual value (i.e. reduced cost) of the structural
+// variable associated with j-th column.
+func (p *Prob) ColDual(j int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_get_col_dual(p.p.p, p.p.col("ColDual", j)))
+}
+
+// RowStats returns statuses of all auxiliary variables. stat[1]..stat[m]
+// are the statuses of rows 1..m where m is the number of rows (stat[0]
+// is unused).
+func (p *Prob) RowStats() []VarStat {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	buf := make([]int32, p.NumRows()+1)
+	buf_ := (*reflect.SliceHeader)(unsafe.Pointer(&buf))
+	C.get_row_stats(p.p.p, (*C.int)(unsafe.Pointer(buf_.Data)))
+	return varStats(buf)
+}
+
+// RowPrims returns primal values of all auxiliary variables.
+// prim[1]..prim[m] are the values for rows 1..m where m is the number
+// of rows (prim[0] is unused).
+func (p *Prob) RowPrims() []float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	prim := make([]float64, p.NumRows()+1)
+	prim_ := (*reflect.SliceHeader)(unsafe.Pointer(&prim))
+	C.get_row_prims(p.p.p, (*C.double)(unsafe.Pointer(prim_.Data)))
+	return prim
+}
+
+// RowDuals returns dual values of all auxiliary variables.
+// dual[1]..dual[m] are the values for rows 1..m where m is the number
+// of rows (dual[0] is unused).
+func (p *Prob) RowDuals() []float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	dual := make([]float64, p.NumRows()+1)
+	dual_ := (*reflect.SliceHeader)(unsafe.Pointer(&dual))
+	C.get_row_duals(p.p.p, (*C.double)(unsafe.Pointer(dual_.Data)))
+	return dual
+}
+
+// ColStats returns statuses of all structural variables.
+// stat[1]..stat[n] are the statuses of columns 1..n where n is the
+// number of columns (stat[0] is unused).
+func (p *Prob) ColStats() []VarStat {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	buf := make([]int32, p.NumCols()+1)
+	buf_ := (*reflect.SliceHeader)(unsafe.Pointer(&buf))
+	C.get_col_stats(p.p.p, (*C.int)(unsafe.Pointer(buf_.Data)))
+	return varStats(buf)
+}
+
+// ColPrims returns primal values of all structural variables.
+// prim[1]..prim[n] are the values for columns 1..n where n is the
+// number of columns (prim[0] is unused).
+func (p *Prob) ColPrims() []float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	prim := make([]float64, p.NumCols()+1)
+	prim_ := (*reflect.SliceHeader)(unsafe.Pointer(&prim))
+	C.get_col_prims(p.p.p, (*C.double)(unsafe.Pointer(prim_.Data)))
+	return prim
+}
+
+// ColDuals returns dual values (reduced costs) of all structural
+// variables. dual[1]..dual[n] are the values for columns 1..n where n
+// is the number of columns (dual[0] is unused).
+func (p *Prob) ColDuals() []float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	dual := make([]float64, p.NumCols()+1)
+	dual_ := (*reflect.SliceHeader)(unsafe.Pointer(&dual))
+	C.get_col_duals(p.p.p, (*C.double)(unsafe.Pointer(dual_.Data)))
+	return dual
+}
+
+// Interior solves LP with the primal-dual interior-point method. The
+// argument parm may by nil (means that default values will be used).
+// See also NewIptcp(). Returns nil if problem have been solved (not
+// necessarly finding optimal solution) otherwise returns an error
+// which is an instanse of OptError (glpk.EFAIL if the problem has no
+// rows/columns, glpk.ENOFEAS if it has no feasible (primal or dual)
+// solution, glpk.ENOCVG on slow convergence or divergence, glpk.EITLIM
+// if iteration limit was exceeded, and glpk.EINSTAB on numerical
+// instability).
+func (p *Prob) Interior(parm *Iptcp) error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	var iptcp *C.glp_iptcp
+	if parm != nil {
+		iptcp = &parm.iptcp
+	}
+	c := newCall(p.p.w)
+	ret := C.go_glp_interior(&c.c, p.p.p, iptcp)
+	if err := c.err(); err != nil {
+		return err
+	}
+	if ret != 0 {
+		return OptError(ret)
+	}
+	return nil
+}
+
+// Iptcp represents interior-point solver control parameters, a set of
+// parameters for Prob.Interior(). Please use NewIptcp() to create
+// Iptcp structure which is properly initialized.
+type Iptcp struct {
+	iptcp C.glp_iptcp
+}
+
+// NewIptcp creates new Iptcp struct (a set of interior-point solver
+// control parameters) to be given as argument of Prob.Interior().
+func NewIptcp() *Iptcp {
+	p := new(Iptcp)
+	C.glp_init_iptcp(&p.iptcp)
+	return p
+}
+
+// SetMsgLev sets message level displayed by the optimization function
+// (default: glpk.MSG_ALL).
+func (p *Iptcp) SetMsgLev(lev MsgLev) {
+	p.iptcp.msg_lev = C.int(lev)
+}
+
+// Ordering algorithm
+type OrdAlg int
+
+const (
+	// Ordering algorithms used prior to Cholesky factorization
+	// (default: glpk.ORD_AMD). Usage example:
+	//
+	//     lp := glpk.New()
+	//     ...
+	//     iptcp := glpk.NewIptcp()
+	//     iptcp.SetOrdAlg(glpk.ORD_QMD)
+	//     lp.Interior(iptcp)
+	//
+	ORD_NONE   = OrdAlg(C.GLP_ORD_NONE)   // natural (original) ordering
+	ORD_QMD    = OrdAlg(C.GLP_ORD_QMD)    // quotient minimum degree (QMD)
+	ORD_AMD    = OrdAlg(C.GLP_ORD_AMD)    // approx. minimum degree (AMD)
+	ORD_SYMAMD = OrdAlg(C.GLP_ORD_SYMAMD) // approx. minimum degree (SYMAMD)
+)
+
+// SetOrdAlg sets ordering algorithm (default: glpk.ORD_AMD).
+func (p *Iptcp) SetOrdAlg(ord_alg OrdAlg) {
+	p.iptcp.ord_alg = C.int(ord_alg)
+}
+
+// IptStatus returns status of the interior-point solution. It is one
+// of glpk.UNDEF (solution is undefined), glpk.OPT (solution is
+// optimal), glpk.INFEAS (solution is infeasible), or glpk.NOFEAS (no
+// feasible primal-dual solution exists).
+func (p *Prob) IptStatus() SolStat {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return SolStat(C.glp_ipt_status(p.p.p))
+}
+
+// IptObjVal returns objective function value of the interior-point
+// solution.
+func (p *Prob) IptObjVal() float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_ipt_obj_val(p.p.p))
+}
+
+// IptRowPrim returns primal value of the auxiliary variable
+// associated with i-th row for the interior-point solution.
+func (p *Prob) IptRowPrim(i int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_ipt_row_prim(p.p.p, p.p.row("IptRowPrim", i)))
+}
+
+// IptRowDual returns dual value (i.e. reduced cost) of the auxiliary
+// variable associated with i-th row for the interior-point solution.
+func (p *Prob) IptRowDual(i int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_ipt_row_dual(p.p.p, p.p.row("IptRowDual", i)))
+}
+
+// IptColPrim returns primal value of the variable associated with
+// j-th column for the interior-point solution.
+func (p *Prob) IptColPrim(j int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_ipt_col_prim(p.p.p, p.p.col("IptColPrim", j)))
+}
+
+// IptColDual returns dual value (i.e. reduced cost) of the variable
+// associated with j-th column for the interior-point solution.
+func (p *Prob) IptColDual(j int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_ipt_col_dual(p.p.p, p.p.col("IptColDual", j)))
+}
+
+// Variable type (kind of structural variable)
+type VarType int
+
+const (
+	CV = VarType(C.GLP_CV) // CV represents a continuous variable
+	IV = VarType(C.GLP_IV) // IV represents an integer variable
+	BV = VarType(C.GLP_BV) // BV represents a binary variable
+)
+
+// SetColKind sets (changes) the kind of j-th column (structural
+// variable). Setting glpk.BV also sets the bounds of the column to
+// [0, 1].
+func (p *Prob) SetColKind(j int, kind VarType) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	c := newCall(p.p.w)
+	C.go_glp_set_col_kind(&c.c, p.p.p, C.int(j), C.int(kind))
+	c.done()
+}
+
+// ColKind returns the kind of j-th column (structural variable).
+func (p *Prob) ColKind(j int) VarType {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return VarType(C.glp_get_col_kind(p.p.p, p.p.col("ColKind", j)))
+}
+
+// NumInt returns number of integer columns (including binary).
+func (p *Prob) NumInt() int {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return int(C.glp_get_num_int(p.p.p))
+}
+
+// NumBin returns number of binary columns (integer columns with
+// bounds [0, 1]).
+func (p *Prob) NumBin() int {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return int(C.glp_get_num_bin(p.p.p))
+}
+
+// Intopt solves MIP with the branch-and-cut method. The argument parm
+// may by nil (means that default values will be used). See also
+// NewIocp(). Unless the MIP presolver is used (Iocp.SetPresolve()) an
+// optimal solution of LP relaxation must be provided (e.g. by calling
+// Prob.Simplex() first). Returns nil if problem have been solved (not
+// necessarly finding optimal solution) otherwise returns an error
+// which is an instanse of OptError.
+func (p *Prob) Intopt(parm *Iocp) error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	var iocp *C.glp_iocp
+	var s *iosState
+	if parm != nil {
+		iocp = &parm.iocp
+		if parm.cb != nil {
+			// parm is copied so that it may be used by
+			// concurrent calls
+			s = &iosState{cb: parm.cb, prob: p, w: p.p.w}
+			iocp_ := parm.iocp
+			defer s.setCallback(&iocp_)()
+			iocp = &iocp_
+			// GLPK does not allow deleting the problem during the
+			// search (and the callback could try it)
+			p.p.solving = true
+			defer func() { p.p.solving = false }()
+		}
+	}
+	c := newCall(p.p.w)
+	ret := C.go_glp_intopt(&c.c, p.p.p, iocp)
+	if err := c.err(); err != nil {
+		// also if a GLPK routine called by the callback failed (in
+		// which case the callback may have panicked with err)
+		return err
+	}
+	if s != nil && s.panicked {
+		panic(s.panicVal)
+	}
+	if ret != 0 {
+		return OptError(ret)
+	}
+	return nil
+}
+
+// Iocp represents MIP solver control parameters, a set of parameters
+// for Prob.Intopt(). Please use NewIocp() to create Iocp structure
+// which is properly initialized.
+type Iocp struct {
+	iocp    C.glp_iocp
+	saveSol *C.char
+	cb      func(t *Tree)
+}
+
+func finalizeIocp(p *Iocp) {
+	if p.saveSol != nil {
+		C.free(unsafe.Pointer(p.saveSol))
+		p.saveSol = nil
+	}
+}
+
+// NewIocp creates new Iocp struct (a set of MIP solver control
+// parameters) to be given as argument of Prob.Intopt().
+func NewIocp() *Iocp {
+	p := new(Iocp)
+	C.glp_init_iocp(&p.iocp)
+	runtime.SetFinalizer(p, finalizeIocp)
+	return p
+}
+
+// SetMsgLev sets message level displayed by the optimization function
+// (default: glpk.MSG_ALL).
+func (p *Iocp) SetMsgLev(lev MsgLev) {
+	p.iocp.msg_lev = C.int(lev)
+}
+
+// Branching technique
+type BrTech int
+
+const (
+	// Branching techniques (default: glpk.BR_DTH). Usage example:
+	//
+	//     lp := glpk.New()
+	//     ...
+	//     iocp := glpk.NewIocp()
+	//     iocp.SetBrTech(glpk.BR_MFV)
+	//     lp.Intopt(iocp)
+	//
+	BR_FFV = BrTech(C.GLP_BR_FFV) // first fractional variable
+	BR_LFV = BrTech(C.GLP_BR_LFV) // last fractional variable
+	BR_MFV = BrTech(C.GLP_BR_MFV) // most fractional variable
+	BR_DTH = BrTech(C.GLP_BR_DTH) // heuristic by Driebeck and Tomlin
+	BR_PCH = BrTech(C.GLP_BR_PCH) // hybrid pseudocost heuristic
+)
+
+// SetBrTech sets branching technique (default: glpk.BR_DTH).
+func (p *Iocp) SetBrTech(tech BrTech) {
+	p.iocp.br_tech = C.int(tech)
+}
+
+// Backtracking technique
+type BtTech int
+
+const (
+	// Backtracking techniques (default: glpk.BT_BLB). Usage example:
+	//
+	//     lp := glpk.New()
+	//     ...
+	//     iocp := glpk.NewIocp()
+	//     iocp.SetBtTech(glpk.BT_DFS)
+	//     lp.Intopt(iocp)
+	//
+	BT_DFS = BtTech(C.GLP_BT_DFS) // depth first search
+	BT_BFS = BtTech(C.GLP_BT_BFS) // breadth first search
+	BT_BLB = BtTech(C.GLP_BT_BLB) // best local bound
+	BT_BPH = BtTech(C.GLP_BT_BPH) // best projection heuristic
+)
+
+// SetBtTech sets backtracking technique (default: glpk.BT_BLB).
+func (p *Iocp) SetBtTech(tech BtTech) {
+	p.iocp.bt_tech = C.int(tech)
+}
+
+// SetTolInt sets absolute tolerance used to check if optimal solution
+// to the current LP relaxation is integer feasible (default: 1e-5).
+func (p *Iocp) SetTolInt(tol float64) {
+	p.iocp.tol_int = C.double(tol)
+}
+
+// SetTolObj sets relative tolerance used to check if the objective
+// value in optimal solution to the current LP relaxation is not
+// better than in the best known integer feasible solution (default:
+// 1e-7).
+func (p *Iocp) SetTolObj(tol float64) {
+	p.iocp.tol_obj = C.double(tol)
+}
+
+// SetTmLim sets searching time limit, in milliseconds (default:
+// math.MaxInt32, i.e. no limit).
+func (p *Iocp) SetTmLim(ms int) {
+	p.iocp.tm_lim = C.int(ms)
+}
+
+// SetOutFrq sets output frequency, in milliseconds (default: 5000).
+// This parameter specifies how frequently the solver sends
+// information about the solution process to the terminal.
+func (p *Iocp) SetOutFrq(ms int) {
+	p.iocp.out_frq = C.int(ms)
+}
+
+// SetOutDly sets output delay, in milliseconds (default: 10000). This
+// parameter specifies how long the solver should delay sending
+// information about solution of the current LP relaxation with the
+// simplex method to the terminal.
+func (p *Iocp) SetOutDly(ms int) {
+	p.iocp.out_dly = C.int(ms)
+}
+
+// Preprocessing technique
+type PpTech int
+
+const (
+	// Preprocessing techniques (default: glpk.PP_ALL). Usage example:
+	//
+	//     lp := glpk.New()
+	//     ...
+	//     iocp := glpk.NewIocp()
+	//     iocp.SetPpTech(glpk.PP_ROOT)
+	//     lp.Intopt(iocp)
+	//
+	PP_NONE = PpTech(C.GLP_PP_NONE) // disable preprocessing
+	PP_ROOT = PpTech(C.GLP_PP_ROOT) // preprocessing only on the root level
+	PP_ALL  = PpTech(C.GLP_PP_ALL)  // preprocessing on all levels
+)
+
+// SetPpTech sets preprocessing technique (default: glpk.PP_ALL).
+func (p *Iocp) SetPpTech(tech PpTech) {
+	p.iocp.pp_tech = C.int(tech)
+}
+
+// SetMipGap sets relative mip gap tolerance (default: 0). If the
+// relative mip gap for currently known best integer feasible solution
+// falls below this tolerance, the solver terminates the search.
+func (p *Iocp) SetMipGap(gap float64) {
+	p.iocp.mip_gap = C.double(gap)
+}
+
+// SetCallback sets the branch-and-cut callback which is called by
+// Prob.Intopt() at various points of the search with a Tree whose
+// Reason() tells why it was called (nil removes the callback). See
+// Tree for details.
+func (p *Iocp) SetCallback(cb func(t *Tree)) {
+	p.cb = cb
+}
+
+func glpBool(on bool) C.int {
+	if on {
+		return C.GLP_ON
+	}
+	return C.GLP_OFF
+}
+
+// SetMirCuts enables or disables generating mixed integer rounding
+// (MIR) cuts (default: disabled).
+func (p *Iocp) SetMirCuts(on bool) {
+	p.iocp.mir_cuts = glpBool(on)
+}
+
+// SetGmiCuts enables or disables generating Gomory's mixed integer
+// cuts (default: disabled).
+func (p *Iocp) SetGmiCuts(on bool) {
+	p.iocp.gmi_cuts = glpBool(on)
+}
+
+// SetCovCuts enables or disables generating mixed cover cuts
+// (default: disabled).
+func (p *Iocp) SetCovCuts(on bool) {
+	p.iocp.cov_cuts = glpBool(on)
+}
+
+// SetClqCuts enables or disables generating clique cuts (default:
+// disabled).
+func (p *Iocp) SetClqCuts(on bool) {
+	p.iocp.clq_cuts = glpBool(on)
+}
+
+// SetPresolve enables or disables using the MIP presolver (default:
+// disabled).
+func (p *Iocp) SetPresolve(on bool) {
+	p.iocp.presolve = glpBool(on)
+}
+
+// SetBinarize enables or disables replacing general integer variables
+// by binary ones (default: disabled). Used only if the MIP presolver
+// is enabled.
+func (p *Iocp) SetBinarize(on bool) {
+	p.iocp.binarize = glpBool(on)
+}
+
+// SetFpHeur enables or disables applying the feasibility pump
+// heuristic (default: disabled).
+func (p *Iocp) SetFpHeur(on bool) {
+	p.iocp.fp_heur = glpBool(on)
+}
+
+// SetPsHeur enables or disables applying the proximity search
+// heuristic (default: disabled). Returns ErrUnsupported if GLPK is
+// older than v4.50.
+func (p *Iocp) SetPsHeur(on bool) error {
+	if C.iocp_set_ps_heur(&p.iocp, glpBool(on)) == 0 {
+		return ErrUnsupported
+	}
+	return nil
+}
+
+// SetPsTmLim sets time limit, in milliseconds, for the proximity
+// search heuristic (default: 60000). Returns ErrUnsupported if GLPK is
+// older than v4.50.
+func (p *Iocp) SetPsTmLim(ms int) error {
+	if C.iocp_set_ps_tm_lim(&p.iocp, C.int(ms)) == 0 {
+		return ErrUnsupported
+	}
+	return nil
+}
+
+// SetSrHeur enables or disables applying the simple rounding
+// heuristic (default: enabled). Returns ErrUnsupported if GLPK is
+// older than v4.57.
+func (p *Iocp) SetSrHeur(on bool) error {
+	if C.iocp_set_sr_heur(&p.iocp, glpBool(on)) == 0 {
+		return ErrUnsupported
+	}
+	return nil
+}
+
+// SetUseSol enables or disables using the existing integer feasible
+// solution (the one currently stored in the problem object) as an
+// initial incumbent (default: disabled). Returns ErrUnsupported if
+// GLPK is older than v4.52.
+func (p *Iocp) SetUseSol(on bool) error {
+	if C.iocp_set_use_sol(&p.iocp, glpBool(on)) == 0 {
+		return ErrUnsupported
+	}
+	return nil
+}
+
+// SetSaveSol sets the name of a file to which the solver writes every
+// new integer feasible solution found (the name may contain "*" which
+// is replaced by the solution number). An empty name disables saving
+// (default). Returns ErrUnsupported if GLPK is older than v4.52.
+func (p *Iocp) SetSaveSol(fname string) error {
+	var s *C.char
+	if fname != "" {
+		s = C.CString(fname)
+	}
+	if C.iocp_set_save_sol(&p.iocp, s) == 0 {
+		if s != nil {
+			C.free(unsafe.Pointer(s))
+		}
+		return ErrUnsupported
+	}
+	if p.saveSol != nil {
+		C.free(unsafe.Pointer(p.saveSol))
+	}
+	p.saveSol = s
+	return nil
+}
+
+// SetAlien enables or disables using an alien solver for solving LP
+// relaxations (default: disabled). Returns ErrUnsupported if GLPK is
+// older than v4.52.
+func (p *Iocp) SetAlien(on bool) error {
+	if C.iocp_set_alien(&p.iocp, glpBool(on)) == 0 {
+		return ErrUnsupported
+	}
+	return nil
+}
+
+// SetFlip enables or disables using the long-step ratio test for
+// re-optimizing LP relaxations with the dual simplex (default:
+// disabled). Returns ErrUnsupported if GLPK is older than v4.60.
+func (p *Iocp) SetFlip(on bool) error {
+	if C.iocp_set_flip(&p.iocp, glpBool(on)) == 0 {
+		return ErrUnsupported
+	}
+	return nil
+}
+
+// MipStatus returns status of the MIP solution. It is one of
+// glpk.UNDEF (solution is undefined), glpk.OPT (solution is optimal),
+// glpk.FEAS (solution is integer feasible but its optimality has not
+// been proven), or glpk.NOFEAS (problem has no integer feasible
+// solution).
+func (p *Prob) MipStatus() SolStat {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return SolStat(C.glp_mip_status(p.p.p))
+}
+
+// MipObjVal returns objective function value of the MIP solution.
+func (p *Prob) MipObjVal() float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_mip_obj_val(p.p.p))
+}
+
+// MipRowVal returns value of the auxiliary variable associated with
+// i-th row for the MIP solution.
+func (p *Prob) MipRowVal(i int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_mip_row_val(p.p.p, p.p.row("MipRowVal", i)))
+}
+
+// MipColVal returns value of the variable associated with j-th column
+// for the MIP solution.
+func (p *Prob) MipColVal(j int) float64 {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	return float64(C.glp_mip_col_val(p.p.p, p.p.col("MipColVal", j)))
+}
@@ -20,6 +20,7 @@ package glpk
 import (
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -311,3 +312,476 @@ func TestGarbageCollection(t *testing.T) {
 		lp2.Delete()
 	}
 }
+
+func TestSetGetColKind(t *testing.T) {
+	lp := New()
+	lp.AddCols(3)
+	lp.SetColKind(2, IV)
+	lp.SetColKind(3, BV)
+	for j, kind := range []VarType{CV, IV, BV} {
+		if got := lp.ColKind(j + 1); got != kind {
+			t.Errorf("Got kind %d of column %d expected %d", got, j+1, kind)
+		}
+	}
+	if n := lp.NumInt(); n != 2 {
+		t.Errorf("Got %d integer columns expected 2", n)
+	}
+	if n := lp.NumBin(); n != 1 {
+		t.Errorf("Got %d binary columns expected 1", n)
+	}
+	lp.Delete()
+}
+
+// TestKnapsack solves a small 0-1 knapsack problem
+//
+//     maximize 10 x1 + 13 x2 + 7 x3 + 8 x4
+//     subject to 5 x1 + 7 x2 + 4 x3 + 3 x4 <= 14
+//
+// with binary x1, x2, x3, x4.
+func TestKnapsack(t *testing.T) {
+	lp := New()
+	lp.SetObjDir(MAX)
+	lp.AddRows(1)
+	lp.SetRowBnds(1, UP, 0, 14)
+	lp.AddCols(4)
+	for j, c := range []float64{10, 13, 7, 8} {
+		lp.SetColKind(j+1, BV)
+		lp.SetObjCoef(j+1, c)
+	}
+	lp.SetMatRow(1, []int32{0, 1, 2, 3, 4}, []float64{0, 5, 7, 4, 3})
+
+	iocp := NewIocp()
+	iocp.SetMsgLev(MSG_ERR)
+	iocp.SetPresolve(true)
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Intopt error: %v", err)
+	}
+	if s := lp.MipStatus(); s != OPT {
+		t.Errorf("expected optimal solution, but got %d", s)
+	}
+	CheckClose(t, lp.MipObjVal(), 28)
+	CheckClose(t, lp.MipRowVal(1), 14)
+	for j, x := range []float64{0, 1, 1, 1} {
+		CheckClose(t, lp.MipColVal(j+1), x)
+	}
+	lp.Delete()
+}
+
+func TestIntoptNoFeas(t *testing.T) {
+	// 2 x = 1 has a feasible LP relaxation but no integer solution
+	lp := New()
+	lp.AddRows(1)
+	lp.SetRowBnds(1, FX, 1, 1)
+	lp.AddCols(1)
+	lp.SetColBnds(1, DB, 0, 10)
+	lp.SetColKind(1, IV)
+	lp.SetMatRow(1, []int32{0, 1}, []float64{0, 2})
+
+	smcp := NewSmcp()
+	smcp.SetMsgLev(MSG_ERR)
+	if err := lp.Simplex(smcp); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	iocp := NewIocp()
+	iocp.SetMsgLev(MSG_ERR)
+	if err := lp.Intopt(iocp); err != nil {
+		t.Errorf("Intopt error: %v", err)
+	}
+	if s := lp.MipStatus(); s != NOFEAS {
+		t.Errorf("expected no feasible solution, but got %d", s)
+	}
+
+	// with presolver an infeasible LP relaxation is reported as error
+	lp.SetRowBnds(1, FX, 30, 30)
+	iocp.SetPresolve(true)
+	if err := lp.Intopt(iocp); err != ENOPFS {
+		t.Errorf("expected %v but got %v", ENOPFS, err)
+	}
+	if s := lp.MipStatus(); s != NOFEAS {
+		t.Errorf("expected no feasible solution, but got %d", s)
+	}
+	lp.Delete()
+}
+
+// sampleProb returns the problem from TestExample.
+func sampleProb() *Prob {
+	lp := New()
+	lp.SetProbName("sample")
+	lp.SetObjName("Z")
+	lp.SetObjDir(MAX)
+	lp.AddRows(3)
+	for i := 0; i < 3; i++ {
+		lp.SetRowName(i+1, fmt.Sprintf("%c", 'p'+i))
+	}
+	lp.SetRowBnds(1, UP, 0, 100.0)
+	lp.SetRowBnds(2, UP, 0, 600.0)
+	lp.SetRowBnds(3, UP, 0, 300.0)
+	lp.AddCols(3)
+	for i := 0; i < 3; i++ {
+		lp.SetColName(i+1, fmt.Sprintf("x%d", i))
+		lp.SetColBnds(i+1, LO, 0.0, 0.0)
+	}
+	lp.SetObjCoef(1, 10.0)
+	lp.SetObjCoef(2, 6.0)
+	lp.SetObjCoef(3, 4.0)
+	ind := []int32{0, 1, 2, 3}
+	mat := [][]float64{
+		{0, 1.0, 1.0, 1.0},
+		{0, 10.0, 4.0, 5.0},
+		{0, 2.0, 2.0, 6.0}}
+	for i := 0; i < 3; i++ {
+		lp.SetMatRow(i+1, ind, mat[i])
+	}
+	return lp
+}
+
+func CheckCloseTol(t *testing.T, v1, v2, tol float64) {
+	if math.Abs(v1-v2) > tol {
+		t.Errorf("values %g and %g differ by %g", v1, v2, v1-v2)
+	}
+}
+
+func TestInterior(t *testing.T) {
+	lp := sampleProb()
+	iptcp := NewIptcp()
+	iptcp.SetMsgLev(MSG_ERR)
+	iptcp.SetOrdAlg(ORD_QMD)
+	if err := lp.Interior(iptcp); err != nil {
+		t.Fatalf("Interior error: %v", err)
+	}
+	if s := lp.IptStatus(); s != OPT {
+		t.Errorf("expected optimal solution, but got %d", s)
+	}
+	const tol = 1e-6
+	CheckCloseTol(t, lp.IptObjVal(), 733+1.0/3, tol)
+	CheckCloseTol(t, lp.IptColPrim(1), 33+1.0/3, tol)
+	CheckCloseTol(t, lp.IptColPrim(2), 66+2.0/3, tol)
+	CheckCloseTol(t, lp.IptColPrim(3), 0, tol)
+	CheckCloseTol(t, lp.IptRowPrim(1), 100, tol)
+	CheckCloseTol(t, lp.IptRowPrim(2), 600, tol)
+	CheckCloseTol(t, lp.IptRowPrim(3), 200, tol)
+	CheckCloseTol(t, lp.IptRowDual(1), 10.0/3, tol)
+	CheckCloseTol(t, lp.IptRowDual(2), 2.0/3, tol)
+	CheckCloseTol(t, lp.IptRowDual(3), 0, tol)
+	CheckCloseTol(t, lp.IptColDual(3), -8.0/3, tol)
+	lp.Delete()
+}
+
+func TestInteriorEmpty(t *testing.T) {
+	lp := New()
+	iptcp := NewIptcp()
+	iptcp.SetMsgLev(MSG_OFF)
+	if err := lp.Interior(iptcp); err != EFAIL {
+		t.Errorf("expected %v but got %v", EFAIL, err)
+	}
+	lp.Delete()
+}
+
+var bndsTests = []struct {
+	type_  BndsType
+	lb, ub float64 // as set
+	el, eu float64 // as expected to be returned
+}{
+	{FR, 1, 2, -math.MaxFloat64, math.MaxFloat64},
+	{LO, 1, 2, 1, math.MaxFloat64},
+	{UP, 1, 2, -math.MaxFloat64, 2},
+	{DB, 1, 2, 1, 2},
+	{FX, 3, 3, 3, 3},
+}
+
+func TestSetGetRowBnds(t *testing.T) {
+	lp := New()
+	lp.AddRows(len(bndsTests))
+	for i, b := range bndsTests {
+		lp.SetRowBnds(i+1, b.type_, b.lb, b.ub)
+	}
+	types, lbs, ubs := lp.RowTypes(), lp.RowLBs(), lp.RowUBs()
+	if len(types) != len(bndsTests)+1 || len(lbs) != len(bndsTests)+1 || len(ubs) != len(bndsTests)+1 {
+		t.Fatalf("Got slices of lengths %d, %d, %d expected %d", len(types), len(lbs), len(ubs), len(bndsTests)+1)
+	}
+	for i, b := range bndsTests {
+		if got := lp.RowType(i + 1); got != b.type_ || types[i+1] != b.type_ {
+			t.Errorf("Got type %d (bulk %d) of row %d expected %d", got, types[i+1], i+1, b.type_)
+		}
+		if got := lp.RowLB(i + 1); got != b.el || lbs[i+1] != b.el {
+			t.Errorf("Got lower bound %g (bulk %g) of row %d expected %g", got, lbs[i+1], i+1, b.el)
+		}
+		if got := lp.RowUB(i + 1); got != b.eu || ubs[i+1] != b.eu {
+			t.Errorf("Got upper bound %g (bulk %g) of row %d expected %g", got, ubs[i+1], i+1, b.eu)
+		}
+	}
+	lp.Delete()
+}
+
+func TestSetGetColBnds(t *testing.T) {
+	lp := New()
+	lp.AddCols(len(bndsTests))
+	for j, b := range bndsTests {
+		lp.SetColBnds(j+1, b.type_, b.lb, b.ub)
+	}
+	types, lbs, ubs := lp.ColTypes(), lp.ColLBs(), lp.ColUBs()
+	if len(types) != len(bndsTests)+1 || len(lbs) != len(bndsTests)+1 || len(ubs) != len(bndsTests)+1 {
+		t.Fatalf("Got slices of lengths %d, %d, %d expected %d", len(types), len(lbs), len(ubs), len(bndsTests)+1)
+	}
+	for j, b := range bndsTests {
+		if got := lp.ColType(j + 1); got != b.type_ || types[j+1] != b.type_ {
+			t.Errorf("Got type %d (bulk %d) of column %d expected %d", got, types[j+1], j+1, b.type_)
+		}
+		if got := lp.ColLB(j + 1); got != b.el || lbs[j+1] != b.el {
+			t.Errorf("Got lower bound %g (bulk %g) of column %d expected %g", got, lbs[j+1], j+1, b.el)
+		}
+		if got := lp.ColUB(j + 1); got != b.eu || ubs[j+1] != b.eu {
+			t.Errorf("Got upper bound %g (bulk %g) of column %d expected %g", got, ubs[j+1], j+1, b.eu)
+		}
+	}
+	lp.Delete()
+}
+
+func TestNumNz(t *testing.T) {
+	lp := sampleProb()
+	if n := lp.NumNz(); n != 9 {
+		t.Errorf("Got %d non-zeros expected 9", n)
+	}
+	lp.Delete()
+}
+
+func TestBasicSolution(t *testing.T) {
+	lp := sampleProb()
+	smcp := NewSmcp()
+	smcp.SetMsgLev(MSG_ERR)
+	if err := lp.Simplex(smcp); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	rowStat := []VarStat{0, NU, NU, BS}
+	rowPrim := []float64{0, 100, 600, 200}
+	rowDual := []float64{0, 10.0 / 3, 2.0 / 3, 0}
+	colStat := []VarStat{0, BS, BS, NL}
+	colPrim := []float64{0, 33 + 1.0/3, 66 + 2.0/3, 0}
+	colDual := []float64{0, 0, 0, -8.0 / 3}
+	rowStats, rowPrims, rowDuals := lp.RowStats(), lp.RowPrims(), lp.RowDuals()
+	for i := 1; i <= 3; i++ {
+		if s := lp.RowStat(i); s != rowStat[i] || rowStats[i] != s {
+			t.Errorf("Got status %d (bulk %d) of row %d expected %d", s, rowStats[i], i, rowStat[i])
+		}
+		CheckClose(t, lp.RowPrim(i), rowPrim[i])
+		CheckClose(t, rowPrims[i], rowPrim[i])
+		CheckClose(t, lp.RowDual(i), rowDual[i])
+		CheckClose(t, rowDuals[i], rowDual[i])
+	}
+	colStats, colPrims, colDuals := lp.ColStats(), lp.ColPrims(), lp.ColDuals()
+	for j := 1; j <= 3; j++ {
+		if s := lp.ColStat(j); s != colStat[j] || colStats[j] != s {
+			t.Errorf("Got status %d (bulk %d) of column %d expected %d", s, colStats[j], j, colStat[j])
+		}
+		CheckClose(t, lp.ColPrim(j), colPrim[j])
+		CheckClose(t, colPrims[j], colPrim[j])
+		CheckClose(t, lp.ColDual(j), colDual[j])
+		CheckClose(t, colDuals[j], colDual[j])
+	}
+	lp.Delete()
+}
+
+func TestDelRows(t *testing.T) {
+	lp := New()
+	lp.AddRows(5)
+	for i := 1; i <= 5; i++ {
+		lp.SetRowName(i, fmt.Sprintf("r%d", i))
+	}
+	mapping, err := lp.DelRows([]int{4, 2})
+	if err != nil {
+		t.Fatalf("DelRows error: %v", err)
+	}
+	expected := []int{0, 1, 0, 2, 0, 3}
+	if fmt.Sprint(mapping) != fmt.Sprint(expected) {
+		t.Errorf("Got mapping %v expected %v", mapping, expected)
+	}
+	if n := lp.NumRows(); n != 3 {
+		t.Errorf("Got %d rows expected 3", n)
+	}
+	for old, i := range mapping {
+		if i == 0 {
+			continue
+		}
+		if name := lp.RowName(i); name != fmt.Sprintf("r%d", old) {
+			t.Errorf("Got name %#v of row %d expected \"r%d\"", name, i, old)
+		}
+	}
+	for _, rows := range [][]int{{0}, {4}, {1, 3, 1}} {
+		if _, err := lp.DelRows(rows); err == nil {
+			t.Errorf("Expected error when deleting rows %v", rows)
+		}
+	}
+	if n := lp.NumRows(); n != 3 {
+		t.Errorf("Got %d rows expected 3", n)
+	}
+	lp.Delete()
+}
+
+func TestDelCols(t *testing.T) {
+	lp := New()
+	lp.AddCols(4)
+	for j := 1; j <= 4; j++ {
+		lp.SetColName(j, fmt.Sprintf("c%d", j))
+	}
+	mapping, err := lp.DelCols([]int{1})
+	if err != nil {
+		t.Fatalf("DelCols error: %v", err)
+	}
+	expected := []int{0, 0, 1, 2, 3}
+	if fmt.Sprint(mapping) != fmt.Sprint(expected) {
+		t.Errorf("Got mapping %v expected %v", mapping, expected)
+	}
+	if name := lp.ColName(1); name != "c2" {
+		t.Errorf("Got name %#v of column 1 expected \"c2\"", name)
+	}
+	if _, err := lp.DelCols([]int{4}); err == nil {
+		t.Errorf("Expected error when deleting column 4 of 3")
+	}
+	if _, err := lp.DelCols([]int{2, 2}); err == nil {
+		t.Errorf("Expected error when deleting duplicate columns")
+	}
+	if n := lp.NumCols(); n != 3 {
+		t.Errorf("Got %d columns expected 3", n)
+	}
+	lp.Delete()
+}
+
+func TestFindRowCol(t *testing.T) {
+	lp := sampleProb()
+	if i, ok := lp.FindRow("q"); !ok || i != 2 {
+		t.Errorf("Got (%d, %v) for row \"q\" expected (2, true)", i, ok)
+	}
+	if j, ok := lp.FindCol("x2"); !ok || j != 3 {
+		t.Errorf("Got (%d, %v) for column \"x2\" expected (3, true)", j, ok)
+	}
+	if _, ok := lp.FindRow("x0"); ok {
+		t.Errorf("Found row \"x0\" which does not exist")
+	}
+	// the index is maintained when rows are added, renamed or deleted
+	lp.SetColName(lp.AddCols(1), "y")
+	lp.SetRowName(1, "pp")
+	if _, err := lp.DelRows([]int{2}); err != nil {
+		t.Fatalf("DelRows error: %v", err)
+	}
+	if j, ok := lp.FindCol("y"); !ok || j != 4 {
+		t.Errorf("Got (%d, %v) for column \"y\" expected (4, true)", j, ok)
+	}
+	if _, ok := lp.FindRow("p"); ok {
+		t.Errorf("Found row \"p\" which was renamed")
+	}
+	if i, ok := lp.FindRow("r"); !ok || i != 2 {
+		t.Errorf("Got (%d, %v) for row \"r\" expected (2, true)", i, ok)
+	}
+	lp.DeleteIndex()
+	lp.DeleteIndex()
+	if i, ok := lp.FindRow("pp"); !ok || i != 1 {
+		t.Errorf("Got (%d, %v) for row \"pp\" expected (1, true)", i, ok)
+	}
+	lp.Delete()
+}
+
+func TestCheckDup(t *testing.T) {
+	ia := []int32{0, 1, 1, 2}
+	ja := []int32{0, 1, 2, 1}
+	if err := CheckDup(2, 2, ia, ja); err != nil {
+		t.Errorf("CheckDup error: %v", err)
+	}
+	if err := CheckDup(2, 1, ia, ja); err == nil {
+		t.Errorf("Expected out of range error")
+	}
+	ia = append(ia, 1)
+	ja = append(ja, 2)
+	if err := CheckDup(2, 2, ia, ja); err == nil {
+		t.Errorf("Expected duplicate error")
+	}
+	if err := CheckDup(2, 2, []int32{0}, []int32{0}); err != nil {
+		t.Errorf("CheckDup error for empty matrix: %v", err)
+	}
+}
+
+func TestSmcp(t *testing.T) {
+	smcp := NewSmcp()
+	CheckClose(t, smcp.TolBnd(), 1e-7)
+	if smcp.ItLim() != math.MaxInt32 || smcp.Presolve() || smcp.Meth() != PRIMAL {
+		t.Errorf("Unexpected defaults: %v", smcp)
+	}
+	smcp.SetItLim(10)
+	smcp.SetTmLim(1000)
+	smcp.SetObjUL(5)
+	smcp.SetPresolve(true)
+	if smcp.ItLim() != 10 || smcp.TmLim() != 1000 || smcp.ObjUL() != 5 || !smcp.Presolve() {
+		t.Errorf("Parameters not set: %v", smcp)
+	}
+	if s := smcp.String(); !strings.Contains(s, "it_lim: 10,") || !strings.Contains(s, "presolve: true") {
+		t.Errorf("Unexpected String(): %s", s)
+	}
+	major, minor := HeaderVersion()
+	err := smcp.SetExcl(false)
+	if major > 4 || minor >= 60 {
+		if err != nil {
+			t.Errorf("SetExcl error: %v", err)
+		} else if v, _ := smcp.Excl(); v {
+			t.Errorf("Excl not set")
+		}
+	} else if err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported but got %v", err)
+	}
+	if Version() == "" {
+		t.Errorf("Empty GLPK version")
+	}
+}
+
+func TestSmcpItLim(t *testing.T) {
+	lp := sampleProb()
+	smcp := NewSmcp()
+	smcp.SetItLim(1)
+	if err := lp.Simplex(smcp); err != EITLIM {
+		t.Errorf("Expected EITLIM but got %v", err)
+	}
+	lp.Delete()
+}
+
+func TestScaleProb(t *testing.T) {
+	lp := sampleProb()
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	prim := lp.ColPrims()
+	for i := 1; i <= 3; i++ {
+		if lp.Rii(i) != 1 || lp.Sjj(i) != 1 {
+			t.Errorf("Expected unit scale factors for a new problem")
+		}
+	}
+
+	lp.ScaleProb(SF_GM | SF_EQ | SF_2N)
+	scaled := false
+	for i := 1; i <= 3; i++ {
+		if lp.Rii(i) != 1 || lp.Sjj(i) != 1 {
+			scaled = true
+		}
+	}
+	if !scaled {
+		t.Errorf("ScaleProb did not change scale factors")
+	}
+	ind, val := lp.MatRow(2)
+	if !CmpIndicesData(ind, val, []int32{0, 1, 2, 3}, []float64{0, 10, 4, 5}) {
+		t.Errorf("Scaling changed problem data: %v %v", ind, val)
+	}
+	lp.StdBasis()
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	for j := 1; j <= 3; j++ {
+		CheckClose(t, lp.ColPrim(j), prim[j])
+	}
+
+	lp.SetRii(1, 2)
+	lp.SetSjj(3, 0.5)
+	CheckClose(t, lp.Rii(1), 2)
+	CheckClose(t, lp.Sjj(3), 0.5)
+	lp.UnscaleProb()
+	if lp.Rii(1) != 1 || lp.Sjj(3) != 1 {
+		t.Errorf("UnscaleProb did not reset scale factors")
+	}
+	lp.Delete()
+}
@@ -0,0 +1,785 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// #include <glpk.h>
+// #include <stdlib.h>
+// #include "graph.h"
+// #include "guard.h"
+//
+// // create_graph creates a graph and stores the id of the GLPK
+// // environment it belongs to in env.
+// static glp_graph *create_graph(uint64_t *env) {
+//	*env = guard_env();
+//	return glp_create_graph(sizeof(go_vdata), sizeof(go_adata));
+// }
+//
+// static void erase_graph(glp_graph *G) {
+//	glp_erase_graph(G, sizeof(go_vdata), sizeof(go_adata));
+// }
+//
+// // order_out rebuilds the lists of outgoing arcs so that they are in
+// // order of arcs[1..na] (glp_add_arc adds new arcs at the front).
+// static void order_out(glp_graph *G, glp_arc **arcs, int na) {
+//	int i, k;
+//	for (i = 1; i <= G->nv; i++)
+//		G->v[i]->out = NULL;
+//	for (k = na; k >= 1; k--) {
+//		glp_arc *a = arcs[k];
+//		a->t_prev = NULL;
+//		a->t_next = a->tail->out;
+//		if (a->t_next != NULL)
+//			a->t_next->t_prev = a;
+//		a->tail->out = a;
+//	}
+// }
+//
+// // reverse_out reverses the list of outgoing arcs of vertex i.
+// static void reverse_out(glp_graph *G, int i) {
+//	glp_vertex *v = G->v[i];
+//	glp_arc *a = v->out, *prev = NULL, *next;
+//	while (a != NULL) {
+//		next = a->t_next;
+//		a->t_next = prev;
+//		a->t_prev = next;
+//		prev = a;
+//		a = next;
+//	}
+//	v->out = prev;
+// }
+//
+// static int find_vertex(glp_graph *G, const char *name) {
+//	glp_create_v_index(G);
+//	return glp_find_vertex(G, name);
+// }
+//
+// #if GLPK_AT_LEAST(4, 49)
+// static int mincost_relax4(glp_graph *G, int crash, double *sol) {
+//	return glp_mincost_relax4(G, V_RHS, A_LOW, A_CAP, A_COST, crash, sol, A_X, A_RC);
+// }
+// static int has_relax4(void) { return 1; }
+// #else
+// static int mincost_relax4(glp_graph *G, int crash, double *sol) { return 0; }
+// static int has_relax4(void) { return 0; }
+// #endif
+//
+// GUARDED_VOID(go_glp_set_graph_name, (gcall *c, glp_graph *G, const char *name), glp_set_graph_name(G, name))
+// GUARDED(int, go_glp_add_vertices, (gcall *c, glp_graph *G, int nadd), glp_add_vertices(G, nadd))
+// GUARDED_VOID(go_glp_set_vertex_name, (gcall *c, glp_graph *G, int i, const char *name), glp_set_vertex_name(G, i, name))
+// GUARDED(glp_arc *, go_glp_add_arc, (gcall *c, glp_graph *G, int i, int j), glp_add_arc(G, i, j))
+// GUARDED_VOID(go_glp_del_vertices, (gcall *c, glp_graph *G, int ndel, const int num[]), glp_del_vertices(G, ndel, num))
+// GUARDED(int, go_find_vertex, (gcall *c, glp_graph *G, const char *name), find_vertex(G, name))
+// GUARDED(int, go_glp_maxflow_ffalg, (gcall *c, glp_graph *G, int s, int t, double *sol), glp_maxflow_ffalg(G, s, t, A_CAP, sol, A_X, V_CUT))
+// GUARDED(int, go_glp_mincost_okalg, (gcall *c, glp_graph *G, double *sol), glp_mincost_okalg(G, V_RHS, A_LOW, A_CAP, A_COST, sol, A_X, V_PI))
+// GUARDED(int, go_glp_mincost_relax4, (gcall *c, glp_graph *G, int crash, double *sol), mincost_relax4(G, crash, sol))
+// GUARDED(int, go_glp_asnprob_okalg, (gcall *c, int form, glp_graph *G, double *sol), glp_asnprob_okalg(form, G, -1, A_COST, sol, A_ASN))
+// GUARDED(int, go_glp_asnprob_hall, (gcall *c, glp_graph *G), glp_asnprob_hall(G, -1, A_ASN))
+// GUARDED(double, go_glp_cpp, (gcall *c, glp_graph *G), glp_cpp(G, V_T, V_ES, V_LS))
+// GUARDED_VOID(go_glp_maxflow_lp, (gcall *c, glp_prob *P, glp_graph *G, int names, int s, int t), glp_maxflow_lp(P, G, names, s, t, A_CAP))
+// GUARDED_VOID(go_glp_mincost_lp, (gcall *c, glp_prob *P, glp_graph *G, int names), glp_mincost_lp(P, G, names, V_RHS, A_LOW, A_CAP, A_COST))
+// GUARDED(int, go_glp_asnprob_lp, (gcall *c, glp_prob *P, int form, glp_graph *G, int names), glp_asnprob_lp(P, form, G, names, -1, A_COST))
+import "C"
+
+// ErrCyclic is returned by graph algorithms which require an acyclic
+// graph if the graph has a cycle.
+var ErrCyclic = errors.New("glpk: graph has a cycle")
+
+type graph struct {
+	g       *C.glp_graph
+	env     uint64       // id of the GLPK environment g belongs to
+	arcs    []*C.glp_arc // arcs in order of their numbers (arcs[0] is unused)
+	ordered bool         // whether lists of outgoing arcs are in order of arcs (see orderArcs)
+	w       termWriter   // terminal output writer (see SetTermWriter)
+}
+
+// Graph represents a directed graph (network) with data used by the
+// network algorithms of GLPK: supply of vertices and lower bound,
+// capacity and cost of arcs. Use glpk.NewGraph() to create a new
+// graph.
+//
+// Vertices and arcs are numbered from 1. Vertex numbers are assigned
+// by GLPK, arc numbers are assigned by Graph in order of creation of
+// the arcs. Results of the algorithms are returned in slices indexed
+// by vertex or arc numbers (with unused element at index 0).
+type Graph struct {
+	g *graph
+}
+
+// invalid reports whether the graph was deleted (or freed along with
+// its GLPK environment after a fatal error).
+func (g *graph) invalid() bool {
+	return g.g == nil || envDead(g.env)
+}
+
+// free deletes the graph unless it was already freed.
+func (g *graph) free() {
+	if g.g != nil && !envDead(g.env) {
+		C.guard_free(C.GUARD_GRAPH, unsafe.Pointer(g.g), C.uint64_t(g.env))
+	}
+	g.g = nil
+	g.arcs = nil
+	g.w.release()
+	g.w = 0
+}
+
+func finalizeGraph(g *graph) {
+	g.free()
+}
+
+// NewGraph creates a new empty graph.
+func NewGraph() *Graph {
+	var env C.uint64_t
+	g := &graph{g: C.create_graph(&env), env: uint64(env), arcs: make([]*C.glp_arc, 1), ordered: true}
+	runtime.SetFinalizer(g, finalizeGraph)
+	return &Graph{g}
+}
+
+// Delete deletes the graph. Calling Delete on a deleted graph will
+// have no effect. But calling any other method on a deleted graph
+// will panic.
+func (g *Graph) Delete() {
+	g.g.free()
+}
+
+// Erase erases the graph. After erasing the graph is empty as if it
+// were created with glpk.NewGraph().
+func (g *Graph) Erase() {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	C.erase_graph(g.g.g)
+	g.g.arcs = g.g.arcs[:1]
+	g.g.ordered = true
+}
+
+// SetName sets (changes) the graph name.
+func (g *Graph) SetName(name string) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	s := C.CString(name)
+	defer C.free(unsafe.Pointer(s))
+	c := newCall(g.g.w)
+	C.go_glp_set_graph_name(&c.c, g.g.g, s)
+	c.done()
+}
+
+// Name returns the graph name.
+func (g *Graph) Name() string {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	return C.GoString(g.g.g.name)
+}
+
+// NumVertices returns the number of vertices.
+func (g *Graph) NumVertices() int {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	return int(g.g.g.nv)
+}
+
+// NumArcs returns the number of arcs.
+func (g *Graph) NumArcs() int {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	return len(g.g.arcs) - 1
+}
+
+// AddVertices adds n vertices to the graph and returns the number of
+// the first of them.
+func (g *Graph) AddVertices(n int) int {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	c := newCall(g.g.w)
+	first := C.go_glp_add_vertices(&c.c, g.g.g, C.int(n))
+	c.done()
+	return int(first)
+}
+
+// loadArcs numbers the arcs of a graph built by GLPK (e.g. read from
+// a file) in order of their tail vertices and, for arcs with the same
+// tail, in order of their creation.
+func (g *graph) loadArcs() {
+	g.arcs = g.arcs[:1]
+	for i := 1; i <= int(g.g.nv); i++ {
+		// GLPK adds new arcs at the front of the list
+		C.reverse_out(g.g, C.int(i))
+		for a := C.vertex(g.g, C.int(i)).out; a != nil; a = a.t_next {
+			g.arcs = append(g.arcs, a)
+		}
+	}
+	g.ordered = true
+}
+
+// orderArcs puts the lists of outgoing arcs in order of arc numbers
+// (which the writers and LP formulations follow). It is done lazily
+// as AddArc adds arcs in constant time at the front of the lists.
+func (g *graph) orderArcs() {
+	if !g.ordered {
+		C.order_out(g.g, &g.arcs[0], C.int(len(g.arcs)-1))
+		g.ordered = true
+	}
+}
+
+// vertex returns vertex i or panics with an *IndexError if there is
+// no such vertex.
+func (g *graph) vertex(op string, i int) *C.glp_vertex {
+	if err := checkIndex(op, "vertex", i, int(g.g.nv)); err != nil {
+		panic(err)
+	}
+	return C.vertex(g.g, C.int(i))
+}
+
+// vdata returns the data of vertex i.
+func (g *graph) vdata(op string, i int) *C.go_vdata {
+	return (*C.go_vdata)(g.vertex(op, i).data)
+}
+
+// adata returns the data of arc a or panics with an *IndexError if
+// there is no such arc.
+func (g *graph) adata(op string, a int) *C.go_adata {
+	if err := checkIndex(op, "arc", a, len(g.arcs)-1); err != nil {
+		panic(err)
+	}
+	return (*C.go_adata)(g.arcs[a].data)
+}
+
+// SetVertexName sets (changes) the name of vertex i.
+func (g *Graph) SetVertexName(i int, name string) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.vertex("SetVertexName", i)
+	s := C.CString(name)
+	defer C.free(unsafe.Pointer(s))
+	c := newCall(g.g.w)
+	C.go_glp_set_vertex_name(&c.c, g.g.g, C.int(i), s)
+	c.done()
+}
+
+// VertexName returns the name of vertex i.
+func (g *Graph) VertexName(i int) string {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	return C.GoString(g.g.vertex("VertexName", i).name)
+}
+
+// FindVertex returns the number of the vertex with the given name or
+// 0 if there is no such vertex.
+func (g *Graph) FindVertex(name string) int {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	s := C.CString(name)
+	defer C.free(unsafe.Pointer(s))
+	c := newCall(g.g.w)
+	i := C.go_find_vertex(&c.c, g.g.g, s)
+	c.done()
+	return int(i)
+}
+
+// AddArc adds an arc from vertex i to vertex j and returns its number.
+func (g *Graph) AddArc(i, j int) int {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.vertex("AddArc", i)
+	g.g.vertex("AddArc", j)
+	c := newCall(g.g.w)
+	a := C.go_glp_add_arc(&c.c, g.g.g, C.int(i), C.int(j))
+	c.done()
+	g.g.arcs = append(g.g.arcs, a)
+	g.g.ordered = false
+	return len(g.g.arcs) - 1
+}
+
+// Arc returns the tail and the head vertex of arc a.
+func (g *Graph) Arc(a int) (tail, head int) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.adata("Arc", a)
+	return int(g.g.arcs[a].tail.i), int(g.g.arcs[a].head.i)
+}
+
+// DelArc deletes arc a. The remaining arcs are renumbered preserving
+// their order.
+func (g *Graph) DelArc(a int) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.adata("DelArc", a)
+	C.glp_del_arc(g.g.g, g.g.arcs[a])
+	g.g.arcs = append(g.g.arcs[:a], g.g.arcs[a+1:]...)
+}
+
+// DelVertices deletes vertices with (1-based) numbers given in num
+// along with all arcs incident to them. The remaining vertices and
+// arcs are renumbered preserving their order. It returns the mapping
+// from old to new vertex numbers: mapping[i] is the new number of the
+// vertex which had number i (or 0 if it was deleted), and mapping[0]
+// is unused. An error is returned (and no vertices are deleted) if num
+// contains duplicate or out of range vertex numbers.
+func (g *Graph) DelVertices(num []int) (mapping []int, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	num_, mapping, err := delIndices("vertex", num, g.NumVertices())
+	if err != nil {
+		return nil, err
+	}
+	if len(num) == 0 {
+		return mapping, nil
+	}
+	arcs := g.g.arcs[:1]
+	for _, a := range g.g.arcs[1:] {
+		if mapping[a.tail.i] != 0 && mapping[a.head.i] != 0 {
+			arcs = append(arcs, a)
+		}
+	}
+	c := newCall(g.g.w)
+	C.go_glp_del_vertices(&c.c, g.g.g, C.int(len(num)), (*C.int)(unsafe.Pointer(&num_[0])))
+	c.done()
+	g.g.arcs = arcs
+	return mapping, nil
+}
+
+// SetSupply sets the supply of vertex i (negative value is a demand)
+// used by MinCost() (default: 0).
+func (g *Graph) SetSupply(i int, rhs float64) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.vdata("SetSupply", i).rhs = C.double(rhs)
+}
+
+// Supply returns the supply of vertex i.
+func (g *Graph) Supply(i int) float64 {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	return float64(g.g.vdata("Supply", i).rhs)
+}
+
+// SetDuration sets the duration of the job represented by vertex i
+// used by CPP() (default: 0).
+func (g *Graph) SetDuration(i int, t float64) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.vdata("SetDuration", i).t = C.double(t)
+}
+
+// Duration returns the duration of the job represented by vertex i.
+func (g *Graph) Duration(i int) float64 {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	return float64(g.g.vdata("Duration", i).t)
+}
+
+// SetWeight sets the weight of vertex i (default: 0).
+func (g *Graph) SetWeight(i int, w float64) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.vdata("SetWeight", i).wgt = C.double(w)
+}
+
+// Weight returns the weight of vertex i.
+func (g *Graph) Weight(i int) float64 {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	return float64(g.g.vdata("Weight", i).wgt)
+}
+
+// SetLow sets the lower bound of the flow through arc a (default: 0).
+func (g *Graph) SetLow(a int, low float64) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.adata("SetLow", a).low = C.double(low)
+}
+
+// Low returns the lower bound of the flow through arc a.
+func (g *Graph) Low(a int) float64 {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	return float64(g.g.adata("Low", a).low)
+}
+
+// SetCap sets the capacity (upper bound of the flow) of arc a
+// (default: 0).
+func (g *Graph) SetCap(a int, cap float64) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.adata("SetCap", a).cap = C.double(cap)
+}
+
+// Cap returns the capacity of arc a.
+func (g *Graph) Cap(a int) float64 {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	return float64(g.g.adata("Cap", a).cap)
+}
+
+// SetCost sets the per-unit cost of the flow through arc a (default:
+// 0). It is also the cost of assigning the tail of a to its head in
+// the assignment problem.
+func (g *Graph) SetCost(a int, cost float64) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.adata("SetCost", a).cost = C.double(cost)
+}
+
+// Cost returns the per-unit cost of the flow through arc a.
+func (g *Graph) Cost(a int) float64 {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	return float64(g.g.adata("Cost", a).cost)
+}
+
+// Flow returns the flow through arc a found by the last call to
+// MaxFlow(), MinCost() or MinCostRelax4().
+func (g *Graph) Flow(a int) float64 {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	return float64(g.g.adata("Flow", a).x)
+}
+
+// flows returns the flows through all arcs.
+func (g *graph) flows() []float64 {
+	x := make([]float64, len(g.arcs))
+	for k, a := range g.arcs[1:] {
+		x[k+1] = float64((*C.go_adata)(a.data).x)
+	}
+	return x
+}
+
+// MaxFlow finds the maximal flow from vertex s to vertex t with the
+// Ford-Fulkerson algorithm using arc capacities (see SetCap), which
+// must be non-negative integers. It returns the total flow, the flow
+// through each arc and the minimal cut: cut[i] is true if vertex i is
+// on the side of s. The error is glpk.EDATA if some capacity is
+// invalid.
+func (g *Graph) MaxFlow(s, t int) (sol float64, flow []float64, cut []bool, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.vertex("MaxFlow", s)
+	g.g.vertex("MaxFlow", t)
+	if s == t {
+		return 0, nil, nil, fmt.Errorf("glpk: MaxFlow: source and sink are the same vertex %d", s)
+	}
+	var sol_ C.double
+	c := newCall(g.g.w)
+	ret := C.go_glp_maxflow_ffalg(&c.c, g.g.g, C.int(s), C.int(t), &sol_)
+	if err := c.err(); err != nil {
+		return 0, nil, nil, err
+	}
+	if ret != 0 {
+		return 0, nil, nil, OptError(ret)
+	}
+	n := g.NumVertices()
+	cut = make([]bool, n+1)
+	for i := 1; i <= n; i++ {
+		cut[i] = g.g.vdata("MaxFlow", i).cut != 0
+	}
+	return float64(sol_), g.g.flows(), cut, nil
+}
+
+// MinCost finds the minimal cost flow satisfying supplies of vertices
+// (see SetSupply) and bounds of arcs (see SetLow and SetCap) with the
+// out-of-kilter algorithm. All data must be integer. It returns the
+// total cost, the flow through each arc and the potential of each
+// vertex. The error is glpk.ENOPFS if there is no feasible flow,
+// glpk.EDATA if the data is invalid, glpk.ERANGE if an integer
+// overflow occurred and glpk.EFAIL if the solver failed.
+func (g *Graph) MinCost() (sol float64, flow, pi []float64, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	var sol_ C.double
+	c := newCall(g.g.w)
+	ret := C.go_glp_mincost_okalg(&c.c, g.g.g, &sol_)
+	if err := c.err(); err != nil {
+		return 0, nil, nil, err
+	}
+	if ret != 0 {
+		return 0, nil, nil, OptError(ret)
+	}
+	n := g.NumVertices()
+	pi = make([]float64, n+1)
+	for i := 1; i <= n; i++ {
+		pi[i] = float64(g.g.vdata("MinCost", i).pi)
+	}
+	return float64(sol_), g.g.flows(), pi, nil
+}
+
+// MinCostRelax4 is like MinCost but uses the RELAX-IV algorithm (with
+// the initial crash procedure if crash is true) and returns reduced
+// costs of arcs instead of potentials of vertices. It returns
+// ErrUnsupported if GLPK is older than 4.49.
+func (g *Graph) MinCostRelax4(crash bool) (sol float64, flow, rc []float64, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	if C.has_relax4() == 0 {
+		return 0, nil, nil, ErrUnsupported
+	}
+	crash_ := 0
+	if crash {
+		crash_ = 1
+	}
+	var sol_ C.double
+	c := newCall(g.g.w)
+	ret := C.go_glp_mincost_relax4(&c.c, g.g.g, C.int(crash_), &sol_)
+	if err := c.err(); err != nil {
+		return 0, nil, nil, err
+	}
+	if ret != 0 {
+		return 0, nil, nil, OptError(ret)
+	}
+	rc = make([]float64, len(g.g.arcs))
+	for k, a := range g.g.arcs[1:] {
+		rc[k+1] = float64((*C.go_adata)(a.data).rc)
+	}
+	return float64(sol_), g.g.flows(), rc, nil
+}
+
+// Assignment problem formulation
+type AsnForm int
+
+const (
+	ASN_MIN = AsnForm(C.GLP_ASN_MIN) // perfect matching of minimal cost
+	ASN_MAX = AsnForm(C.GLP_ASN_MAX) // perfect matching of maximal cost
+	ASN_MMP = AsnForm(C.GLP_ASN_MMP) // (not necessarily perfect) matching of maximal cost
+)
+
+// assignment returns the arcs of the assignment found.
+func (g *graph) assignment() []bool {
+	x := make([]bool, len(g.arcs))
+	for k, a := range g.arcs[1:] {
+		x[k+1] = (*C.go_adata)(a.data).asn != 0
+	}
+	return x
+}
+
+// Assignment solves the assignment problem with the out-of-kilter
+// algorithm. The graph must be bipartite with all arcs going from one
+// set of vertices to the other and the cost of assigning the tail of
+// an arc to its head given by arc cost (see SetCost), which must be
+// integer. It returns the total cost and x[a] which is true if arc a
+// is in the assignment. The error is glpk.EDATA if the graph is not
+// an assignment problem or data is invalid, glpk.ENOPFS if there is
+// no perfect matching (for ASN_MIN and ASN_MAX), glpk.ERANGE if an
+// integer overflow occurred and glpk.EFAIL if the solver failed.
+func (g *Graph) Assignment(form AsnForm) (sol float64, x []bool, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	var sol_ C.double
+	c := newCall(g.g.w)
+	ret := C.go_glp_asnprob_okalg(&c.c, C.int(form), g.g.g, &sol_)
+	if err := c.err(); err != nil {
+		return 0, nil, err
+	}
+	if ret != 0 {
+		return 0, nil, OptError(ret)
+	}
+	return float64(sol_), g.g.assignment(), nil
+}
+
+// AssignmentHall finds a matching of maximal cardinality (ignoring
+// costs) in a graph of an assignment problem (see Assignment) with the
+// Hall's algorithm. It returns the cardinality of the matching and
+// x[a] which is true if arc a is in the matching. The error is
+// glpk.EDATA if the graph is not an assignment problem.
+func (g *Graph) AssignmentHall() (card int, x []bool, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	c := newCall(g.g.w)
+	ret := C.go_glp_asnprob_hall(&c.c, g.g.g)
+	if err := c.err(); err != nil {
+		return 0, nil, err
+	}
+	if ret < 0 {
+		return 0, nil, EDATA
+	}
+	return int(ret), g.g.assignment(), nil
+}
+
+// acyclic reports whether the graph has no cycles.
+func (g *graph) acyclic() bool {
+	n := int(g.g.nv)
+	in := make([]int, n+1)
+	out := make([][]int, n+1)
+	for _, a := range g.arcs[1:] {
+		i, j := int(a.tail.i), int(a.head.i)
+		out[i] = append(out[i], j)
+		in[j]++
+	}
+	var queue []int
+	for i := 1; i <= n; i++ {
+		if in[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for k := 0; k < len(queue); k++ {
+		for _, j := range out[queue[k]] {
+			if in[j]--; in[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+	return len(queue) == n
+}
+
+// CPP solves the critical path problem of a project network in which
+// vertices represent jobs with given durations (see SetDuration) and
+// an arc from i to j means that job j cannot start until job i is
+// finished. It returns the minimal duration of the project and the
+// earliest and the latest start times of all jobs. The error is
+// ErrCyclic if the graph has a cycle.
+func (g *Graph) CPP() (length float64, es, ls []float64, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	n := g.NumVertices()
+	for i := 1; i <= n; i++ {
+		if t := g.Duration(i); t < 0 || !finite(t) {
+			return 0, nil, nil, &ValueError{"CPP", "job duration", i, t}
+		}
+	}
+	if !g.g.acyclic() {
+		return 0, nil, nil, ErrCyclic
+	}
+	c := newCall(g.g.w)
+	length_ := C.go_glp_cpp(&c.c, g.g.g)
+	if err := c.err(); err != nil {
+		return 0, nil, nil, err
+	}
+	es = make([]float64, n+1)
+	ls = make([]float64, n+1)
+	for i := 1; i <= n; i++ {
+		d := g.g.vdata("CPP", i)
+		es[i], ls[i] = float64(d.es), float64(d.ls)
+	}
+	return float64(length_), es, ls, nil
+}
+
+// lpArcs returns the numbers of arcs in order of columns of the LP
+// formulations built by GLPK (which adds columns for the outgoing
+// arcs of vertices 1, 2, ... so that the arcs are ordered by their
+// tails).
+func (g *graph) lpArcs() []int {
+	num := make(map[*C.glp_arc]int, len(g.arcs))
+	for k, a := range g.arcs[1:] {
+		num[a] = k + 1
+	}
+	arcs := make([]int, 1, len(g.arcs))
+	for i := 1; i <= int(g.g.nv); i++ {
+		for a := C.vertex(g.g, C.int(i)).out; a != nil; a = a.t_next {
+			arcs = append(arcs, num[a])
+		}
+	}
+	return arcs
+}
+
+// MaxFlowLP returns the LP formulation of the maximum flow problem
+// solved by MaxFlow(). Rows of the problem correspond to vertices.
+// Columns correspond to arcs: column j to arc arcs[j] (arcs[0] is
+// unused). If names is true symbolic names of the rows and columns
+// are assigned.
+func (g *Graph) MaxFlowLP(s, t int, names bool) (p *Prob, arcs []int, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.vertex("MaxFlowLP", s)
+	g.g.vertex("MaxFlowLP", t)
+	if s == t {
+		return nil, nil, fmt.Errorf("glpk: MaxFlowLP: source and sink are the same vertex %d", s)
+	}
+	g.g.orderArcs()
+	p = New()
+	c := newCall(g.g.w)
+	C.go_glp_maxflow_lp(&c.c, p.p.p, g.g.g, glpBool(names), C.int(s), C.int(t))
+	if err := c.err(); err != nil {
+		return nil, nil, err
+	}
+	return p, g.g.lpArcs(), nil
+}
+
+// MinCostLP returns the LP formulation of the minimum cost flow
+// problem solved by MinCost(). Rows and columns of the problem are as
+// in MaxFlowLP().
+func (g *Graph) MinCostLP(names bool) (p *Prob, arcs []int, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.orderArcs()
+	p = New()
+	c := newCall(g.g.w)
+	C.go_glp_mincost_lp(&c.c, p.p.p, g.g.g, glpBool(names))
+	if err := c.err(); err != nil {
+		return nil, nil, err
+	}
+	return p, g.g.lpArcs(), nil
+}
+
+// AssignmentLP returns the LP formulation of the assignment problem
+// solved by Assignment(). Rows and columns of the problem are as in
+// MaxFlowLP(). The error is glpk.EDATA if the graph is not an
+// assignment problem.
+func (g *Graph) AssignmentLP(form AsnForm, names bool) (p *Prob, arcs []int, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.orderArcs()
+	p = New()
+	c := newCall(g.g.w)
+	ret := C.go_glp_asnprob_lp(&c.c, p.p.p, C.int(form), g.g.g, glpBool(names))
+	if err := c.err(); err != nil {
+		return nil, nil, err
+	}
+	if ret != 0 {
+		p.Delete()
+		return nil, nil, EDATA
+	}
+	return p, g.g.lpArcs(), nil
+}
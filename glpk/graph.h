@@ -0,0 +1,69 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+#ifndef GO_GLPK_GRAPH_H
+#define GO_GLPK_GRAPH_H
+
+#include <stddef.h>
+#include <glpk.h>
+
+// go_vdata is the data of a vertex of a Graph.
+typedef struct {
+	double rhs; // supply (or demand if negative)
+	double pi;  // potential (node price)
+	double t;   // duration of a job (for glp_cpp)
+	double es;  // earliest start time of a job
+	double ls;  // latest start time of a job
+	double wgt; // weight (for glp_wclique_exact and DIMACS clique data)
+	int cut;    // whether the vertex is in the minimal cut
+	int set;    // whether the vertex is in the maximum clique
+	int num;    // component or topological order number
+} go_vdata;
+
+// go_adata is the data of an arc of a Graph.
+typedef struct {
+	double low;  // lower bound of the flow
+	double cap;  // capacity (upper bound of the flow)
+	double cost; // per-unit cost of the flow
+	double x;    // flow
+	double rc;   // reduced cost
+	int asn;     // whether the arc is in the assignment
+} go_adata;
+
+enum {
+	V_RHS = offsetof(go_vdata, rhs),
+	V_PI = offsetof(go_vdata, pi),
+	V_T = offsetof(go_vdata, t),
+	V_ES = offsetof(go_vdata, es),
+	V_LS = offsetof(go_vdata, ls),
+	V_WGT = offsetof(go_vdata, wgt),
+	V_CUT = offsetof(go_vdata, cut),
+	V_SET = offsetof(go_vdata, set),
+	V_NUM = offsetof(go_vdata, num),
+	A_LOW = offsetof(go_adata, low),
+	A_CAP = offsetof(go_adata, cap),
+	A_COST = offsetof(go_adata, cost),
+	A_X = offsetof(go_adata, x),
+	A_RC = offsetof(go_adata, rc),
+	A_ASN = offsetof(go_adata, asn),
+};
+
+static inline glp_vertex *vertex(glp_graph *G, int i) {
+	return G->v[i];
+}
+
+#endif
@@ -0,0 +1,316 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"io/ioutil"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// networkGraph returns a network with 4 vertices and arcs (tail,
+// head, capacity, cost): (1,2,3,1), (1,3,2,3), (2,3,1,1), (2,4,2,4),
+// (3,4,3,1).
+func networkGraph() *Graph {
+	g := NewGraph()
+	g.SetTermWriter(ioutil.Discard)
+	g.AddVertices(4)
+	for _, a := range [][4]float64{{1, 2, 3, 1}, {1, 3, 2, 3}, {2, 3, 1, 1}, {2, 4, 2, 4}, {3, 4, 3, 1}} {
+		k := g.AddArc(int(a[0]), int(a[1]))
+		g.SetCap(k, a[2])
+		g.SetCost(k, a[3])
+	}
+	return g
+}
+
+func TestGraph(t *testing.T) {
+	g := networkGraph()
+	defer g.Delete()
+	g.SetName("net")
+	if g.Name() != "net" || g.NumVertices() != 4 || g.NumArcs() != 5 {
+		t.Errorf("Got graph %q with %d vertices and %d arcs", g.Name(), g.NumVertices(), g.NumArcs())
+	}
+	for i, name := range []string{"", "a", "b", "c", "d"} {
+		if i > 0 {
+			g.SetVertexName(i, name)
+		}
+	}
+	if i := g.FindVertex("c"); i != 3 {
+		t.Errorf("FindVertex(\"c\") = %d expected 3", i)
+	}
+	if i := g.FindVertex("e"); i != 0 {
+		t.Errorf("FindVertex(\"e\") = %d expected 0", i)
+	}
+	if tail, head := g.Arc(4); tail != 2 || head != 4 {
+		t.Errorf("Arc(4) = (%d, %d) expected (2, 4)", tail, head)
+	}
+
+	g.DelArc(1)
+	if tail, head := g.Arc(1); g.NumArcs() != 4 || tail != 1 || head != 3 || g.Cap(1) != 2 {
+		t.Errorf("After DelArc(1) got arc 1 (%d, %d) with capacity %g", tail, head, g.Cap(1))
+	}
+	mapping, err := g.DelVertices([]int{3})
+	if err != nil {
+		t.Fatalf("DelVertices error: %v", err)
+	}
+	if !reflect.DeepEqual(mapping, []int{0, 1, 2, 0, 3}) {
+		t.Errorf("DelVertices mapping %v", mapping)
+	}
+	if tail, head := g.Arc(1); g.NumArcs() != 1 || tail != 2 || head != 3 || g.Cost(1) != 4 {
+		t.Errorf("After DelVertices got %d arcs, arc 1 (%d, %d) with cost %g", g.NumArcs(), tail, head, g.Cost(1))
+	}
+	if g.VertexName(3) != "d" || g.FindVertex("d") != 3 {
+		t.Errorf("Vertex 3 has name %q", g.VertexName(3))
+	}
+	if _, err := g.DelVertices([]int{5}); err == nil {
+		t.Errorf("DelVertices with out of range vertex succeeded")
+	}
+
+	defer func() {
+		if _, ok := recover().(*IndexError); !ok {
+			t.Errorf("Expected panic with *IndexError for invalid arc")
+		}
+	}()
+	g.SetCap(2, 1)
+}
+
+func TestMaxFlow(t *testing.T) {
+	g := networkGraph()
+	defer g.Delete()
+	sol, flow, cut, err := g.MaxFlow(1, 4)
+	if err != nil {
+		t.Fatalf("MaxFlow error: %v", err)
+	}
+	if sol != 5 {
+		t.Errorf("MaxFlow = %g expected 5", sol)
+	}
+	if expected := []float64{0, 3, 2, 1, 2, 3}; !reflect.DeepEqual(flow, expected) {
+		t.Errorf("Got flow %v expected %v", flow, expected)
+	}
+	if expected := []bool{false, true, false, false, false}; !reflect.DeepEqual(cut, expected) {
+		t.Errorf("Got cut %v expected %v", cut, expected)
+	}
+	if g.Flow(3) != 1 {
+		t.Errorf("Flow(3) = %g expected 1", g.Flow(3))
+	}
+	if _, _, _, err := g.MaxFlow(2, 2); err == nil {
+		t.Errorf("MaxFlow with s = t succeeded")
+	}
+	g.SetCap(1, 1.5)
+	if _, _, _, err := g.MaxFlow(1, 4); err != EDATA {
+		t.Errorf("MaxFlow with non-integer capacity returned %v expected EDATA", err)
+	}
+	g.SetCap(1, 3)
+
+	lp, arcs, err := g.MaxFlowLP(1, 4, true)
+	if err != nil {
+		t.Fatalf("MaxFlowLP error: %v", err)
+	}
+	defer lp.Delete()
+	lp.SetTermWriter(ioutil.Discard)
+	if lp.NumRows() != 4 || lp.NumCols() != 5 || len(arcs) != 6 {
+		t.Fatalf("Got LP %d x %d with %d arcs", lp.NumRows(), lp.NumCols(), len(arcs))
+	}
+	// columns follow arc numbers as arcs were added in order of tails
+	if expected := []int{0, 1, 2, 3, 4, 5}; !reflect.DeepEqual(arcs, expected) {
+		t.Errorf("Got LP arcs %v expected %v", arcs, expected)
+	}
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	if lp.ObjVal() != 5 {
+		t.Errorf("LP objective %g expected 5", lp.ObjVal())
+	}
+	for j := 1; j <= 5; j++ {
+		if x := lp.ColPrim(j); x != flow[arcs[j]] {
+			t.Errorf("Column %d (arc %d) has value %g expected %g", j, arcs[j], x, flow[arcs[j]])
+		}
+	}
+}
+
+func TestMinCost(t *testing.T) {
+	g := networkGraph()
+	defer g.Delete()
+	g.SetSupply(1, 4)
+	g.SetSupply(4, -4)
+	if g.Supply(4) != -4 {
+		t.Errorf("Supply(4) = %g", g.Supply(4))
+	}
+	check := func(name string, sol float64, flow []float64) {
+		if sol != 16 {
+			t.Errorf("%s: cost %g expected 16", name, sol)
+		}
+		if expected := []float64{0, 2, 2, 1, 1, 3}; !reflect.DeepEqual(flow, expected) {
+			t.Errorf("%s: got flow %v expected %v", name, flow, expected)
+		}
+	}
+	sol, flow, pi, err := g.MinCost()
+	if err != nil {
+		t.Fatalf("MinCost error: %v", err)
+	}
+	check("MinCost", sol, flow)
+	if len(pi) != 5 {
+		t.Errorf("Got %d potentials", len(pi))
+	}
+	// reduced costs of arcs with flow strictly between bounds are 0
+	for _, a := range []int{1, 4} {
+		tail, head := g.Arc(a)
+		if d := g.Cost(a) - pi[tail] + pi[head]; d != 0 {
+			t.Errorf("Arc %d has reduced cost %g", a, d)
+		}
+	}
+
+	sol, flow, _, err = g.MinCostRelax4(false)
+	if err == ErrUnsupported {
+		t.Log("MinCostRelax4 is not supported")
+	} else if err != nil {
+		t.Errorf("MinCostRelax4 error: %v", err)
+	} else {
+		check("MinCostRelax4", sol, flow)
+	}
+
+	lp, _, err := g.MinCostLP(false)
+	if err != nil {
+		t.Fatalf("MinCostLP error: %v", err)
+	}
+	defer lp.Delete()
+	lp.SetTermWriter(ioutil.Discard)
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	if lp.ObjVal() != 16 {
+		t.Errorf("LP objective %g expected 16", lp.ObjVal())
+	}
+
+	g.SetSupply(1, 6)
+	g.SetSupply(4, -6)
+	if _, _, _, err := g.MinCost(); err != ENOPFS {
+		t.Errorf("MinCost of infeasible problem returned %v expected ENOPFS", err)
+	}
+}
+
+// asnGraph returns an assignment problem in which vertex i = 1..3 is
+// assigned to vertex j = 4..6 with cost cost[i-1][j-4].
+func asnGraph() *Graph {
+	cost := [3][3]float64{{4, 1, 3}, {2, 0, 5}, {3, 2, 2}}
+	g := NewGraph()
+	g.SetTermWriter(ioutil.Discard)
+	g.AddVertices(6)
+	for i := 1; i <= 3; i++ {
+		for j := 4; j <= 6; j++ {
+			g.SetCost(g.AddArc(i, j), cost[i-1][j-4])
+		}
+	}
+	return g
+}
+
+func TestAssignment(t *testing.T) {
+	g := asnGraph()
+	defer g.Delete()
+	for _, tc := range []struct {
+		form AsnForm
+		sol  float64
+		arcs []int
+	}{
+		{ASN_MIN, 5, []int{2, 4, 9}},
+		{ASN_MAX, 11, []int{1, 6, 8}},
+	} {
+		sol, x, err := g.Assignment(tc.form)
+		if err != nil {
+			t.Fatalf("Assignment(%d) error: %v", tc.form, err)
+		}
+		expected := make([]bool, 10)
+		for _, a := range tc.arcs {
+			expected[a] = true
+		}
+		if sol != tc.sol || !reflect.DeepEqual(x, expected) {
+			t.Errorf("Assignment(%d) = %g, %v expected %g, %v", tc.form, sol, x, tc.sol, expected)
+		}
+		lp, _, err := g.AssignmentLP(tc.form, false)
+		if err != nil {
+			t.Fatalf("AssignmentLP(%d) error: %v", tc.form, err)
+		}
+		lp.SetTermWriter(ioutil.Discard)
+		if err := lp.Simplex(nil); err != nil {
+			t.Fatalf("Simplex error: %v", err)
+		}
+		if lp.ObjVal() != tc.sol {
+			t.Errorf("LP objective %g expected %g", lp.ObjVal(), tc.sol)
+		}
+		lp.Delete()
+	}
+
+	card, x, err := g.AssignmentHall()
+	if err != nil {
+		t.Fatalf("AssignmentHall error: %v", err)
+	}
+	n := 0
+	for _, in := range x {
+		if in {
+			n++
+		}
+	}
+	if card != 3 || n != 3 {
+		t.Errorf("AssignmentHall = %d with %d arcs expected 3", card, n)
+	}
+
+	g.AddArc(4, 5)
+	if _, _, err := g.Assignment(ASN_MIN); err != EDATA {
+		t.Errorf("Assignment of invalid graph returned %v expected EDATA", err)
+	}
+	if _, _, err := g.AssignmentLP(ASN_MIN, false); err != EDATA {
+		t.Errorf("AssignmentLP of invalid graph returned %v expected EDATA", err)
+	}
+}
+
+func TestCPP(t *testing.T) {
+	g := NewGraph()
+	defer g.Delete()
+	g.AddVertices(4)
+	for i, d := range []float64{3, 2, 4, 1} {
+		g.SetDuration(i+1, d)
+	}
+	g.AddArc(1, 2)
+	g.AddArc(1, 3)
+	g.AddArc(2, 4)
+	g.AddArc(3, 4)
+	length, es, ls, err := g.CPP()
+	if err != nil {
+		t.Fatalf("CPP error: %v", err)
+	}
+	if length != 8 {
+		t.Errorf("CPP = %g expected 8", length)
+	}
+	if expected := []float64{0, 0, 3, 3, 7}; !reflect.DeepEqual(es, expected) {
+		t.Errorf("Got earliest start times %v expected %v", es, expected)
+	}
+	if expected := []float64{0, 0, 5, 3, 7}; !reflect.DeepEqual(ls, expected) {
+		t.Errorf("Got latest start times %v expected %v", ls, expected)
+	}
+
+	g.SetDuration(2, math.NaN())
+	if _, _, _, err := g.CPP(); err == nil {
+		t.Errorf("CPP with NaN duration succeeded")
+	}
+	g.SetDuration(2, 2)
+	g.AddArc(4, 1)
+	if _, _, _, err := g.CPP(); err != ErrCyclic {
+		t.Errorf("CPP of cyclic graph returned %v expected ErrCyclic", err)
+	}
+}
@@ -0,0 +1,136 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+// #include <glpk.h>
+// #include "graph.h"
+// #include "guard.h"
+//
+// #if GLPK_AT_LEAST(4, 47)
+// static int has_top_sort(void) { return 1; }
+// static int has_wclique(void) { return 1; }
+// #define top_sort glp_top_sort
+// #define wclique_exact glp_wclique_exact
+// #else
+// static int has_top_sort(void) { return 0; }
+// static int has_wclique(void) { return 0; }
+// #define top_sort(G, v_num) 0
+// #define wclique_exact(G, v_wgt, sol, v_set) 0
+// #endif
+//
+// GUARDED(int, go_glp_weak_comp, (gcall *c, glp_graph *G), glp_weak_comp(G, V_NUM))
+// GUARDED(int, go_glp_strong_comp, (gcall *c, glp_graph *G), glp_strong_comp(G, V_NUM))
+// GUARDED(int, go_glp_top_sort, (gcall *c, glp_graph *G), top_sort(G, V_NUM))
+// GUARDED(int, go_glp_wclique_exact, (gcall *c, glp_graph *G, int weighted, double *sol), wclique_exact(G, weighted ? V_WGT : -1, sol, V_SET))
+import "C"
+
+// nums returns the numbers assigned to vertices by a GLPK routine.
+func (g *graph) nums() []int {
+	n := int(g.g.nv)
+	num := make([]int, n+1)
+	for i := 1; i <= n; i++ {
+		num[i] = int((*C.go_vdata)(C.vertex(g.g, C.int(i)).data).num)
+	}
+	return num
+}
+
+// WeakComp finds the weakly connected components of the graph (that
+// is, the connected components when directions of arcs are ignored).
+// It returns the number of components and comp[i] which is the
+// number (from 1 to count) of the component containing vertex i.
+func (g *Graph) WeakComp() (count int, comp []int) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	c := newCall(g.g.w)
+	ret := C.go_glp_weak_comp(&c.c, g.g.g)
+	c.done()
+	return int(ret), g.g.nums()
+}
+
+// StrongComp finds the strongly connected components of the graph. It
+// returns the number of components and comp[i] which is the number
+// (from 1 to count) of the component containing vertex i.
+func (g *Graph) StrongComp() (count int, comp []int) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	c := newCall(g.g.w)
+	ret := C.go_glp_strong_comp(&c.c, g.g.g)
+	c.done()
+	return int(ret), g.g.nums()
+}
+
+// TopSort sorts the vertices of an acyclic graph topologically. It
+// returns order[i] which is the position (from 1) of vertex i so that
+// for every arc from i to j order[i] < order[j]. The error is
+// ErrCyclic if the graph has a cycle (see StrongComp to find it) and
+// ErrUnsupported if GLPK is older than 4.47.
+func (g *Graph) TopSort() (order []int, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	if C.has_top_sort() == 0 {
+		return nil, ErrUnsupported
+	}
+	c := newCall(g.g.w)
+	ret := C.go_glp_top_sort(&c.c, g.g.g)
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	if ret != 0 {
+		return nil, ErrCyclic
+	}
+	return g.g.nums(), nil
+}
+
+// MaxClique finds a maximum weight clique of the graph with an exact
+// algorithm. Arcs are treated as undirected edges. If weighted is true
+// the vertex weights (see SetWeight), which must be non-negative
+// integers, are used, otherwise all vertices have weight 1 (and a
+// clique of maximum size is found). It returns the weight of the
+// clique and in[i] which is true if vertex i is in the clique. The
+// error is glpk.EDATA if some weight is invalid and ErrUnsupported if
+// GLPK is older than 4.47.
+func (g *Graph) MaxClique(weighted bool) (sol float64, in []bool, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	if C.has_wclique() == 0 {
+		return 0, nil, ErrUnsupported
+	}
+	weighted_ := 0
+	if weighted {
+		weighted_ = 1
+	}
+	var sol_ C.double
+	c := newCall(g.g.w)
+	ret := C.go_glp_wclique_exact(&c.c, g.g.g, C.int(weighted_), &sol_)
+	if err := c.err(); err != nil {
+		return 0, nil, err
+	}
+	if ret != 0 {
+		return 0, nil, OptError(ret)
+	}
+	n := g.NumVertices()
+	in = make([]bool, n+1)
+	for i := 1; i <= n; i++ {
+		in[i] = g.g.vdata("MaxClique", i).set != 0
+	}
+	return float64(sol_), in, nil
+}
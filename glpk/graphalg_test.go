@@ -0,0 +1,97 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComponents(t *testing.T) {
+	g := NewGraph()
+	defer g.Delete()
+	g.AddVertices(6)
+	for _, a := range [][2]int{{1, 2}, {2, 3}, {3, 1}, {3, 4}, {5, 6}} {
+		g.AddArc(a[0], a[1])
+	}
+	count, comp := g.WeakComp()
+	if count != 2 || len(comp) != 7 {
+		t.Fatalf("WeakComp returned %d, %v", count, comp)
+	}
+	if comp[1] != comp[4] || comp[5] != comp[6] || comp[1] == comp[5] {
+		t.Errorf("Got weak components %v", comp)
+	}
+	count, comp = g.StrongComp()
+	if count != 4 || len(comp) != 7 {
+		t.Fatalf("StrongComp returned %d, %v", count, comp)
+	}
+	if comp[1] != comp[2] || comp[1] != comp[3] || comp[1] == comp[4] || comp[4] == comp[5] || comp[5] == comp[6] {
+		t.Errorf("Got strong components %v", comp)
+	}
+
+	if _, err := g.TopSort(); err == ErrUnsupported {
+		t.Skip("TopSort is not supported")
+	} else if err != ErrCyclic {
+		t.Errorf("TopSort of cyclic graph returned %v expected ErrCyclic", err)
+	}
+	g.DelArc(3)
+	order, err := g.TopSort()
+	if err != nil {
+		t.Fatalf("TopSort error: %v", err)
+	}
+	seen := make([]bool, 7)
+	for _, k := range order[1:] {
+		if k < 1 || k > 6 || seen[k] {
+			t.Fatalf("TopSort returned invalid order %v", order)
+		}
+		seen[k] = true
+	}
+	for a := 1; a <= g.NumArcs(); a++ {
+		if i, j := g.Arc(a); order[i] >= order[j] {
+			t.Errorf("Vertex %d is not before %d in order %v", i, j, order)
+		}
+	}
+}
+
+func TestMaxClique(t *testing.T) {
+	g := NewGraph()
+	defer g.Delete()
+	g.AddVertices(5)
+	for _, a := range [][2]int{{1, 2}, {1, 3}, {2, 3}, {3, 4}, {4, 5}, {3, 5}} {
+		g.AddArc(a[0], a[1])
+	}
+	for i, w := range []float64{1, 1, 1, 5, 5} {
+		g.SetWeight(i+1, w)
+	}
+	sol, in, err := g.MaxClique(true)
+	if err == ErrUnsupported {
+		t.Skip("MaxClique is not supported")
+	} else if err != nil {
+		t.Fatalf("MaxClique error: %v", err)
+	}
+	if expected := []bool{false, false, false, true, true, true}; sol != 11 || !reflect.DeepEqual(in, expected) {
+		t.Errorf("MaxClique = %g, %v expected 11, %v", sol, in, expected)
+	}
+	if sol, _, err := g.MaxClique(false); err != nil || sol != 3 {
+		t.Errorf("Unweighted MaxClique returned %g, %v expected 3", sol, err)
+	}
+	g.SetWeight(2, 1.5)
+	if _, _, err := g.MaxClique(true); err != EDATA {
+		t.Errorf("MaxClique with non-integer weight returned %v expected EDATA", err)
+	}
+}
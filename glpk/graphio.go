@@ -0,0 +1,397 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// #include <glpk.h>
+// #include <stdlib.h>
+// #include "graph.h"
+// #include "guard.h"
+//
+// enum {
+//	READ_MINCOST, WRITE_MINCOST, READ_MAXFLOW, WRITE_MAXFLOW,
+//	READ_ASNPROB, WRITE_ASNPROB, READ_CCDATA, WRITE_CCDATA,
+//	READ_GRAPH, WRITE_GRAPH
+// };
+//
+// #if GLPK_AT_LEAST(4, 47)
+// static int has_ccdata(void) { return 1; }
+// #define read_ccdata glp_read_ccdata
+// #define write_ccdata glp_write_ccdata
+// #else
+// static int has_ccdata(void) { return 0; }
+// #define read_ccdata(G, v_wgt, fname) 1
+// #define write_ccdata(G, v_wgt, fname) 1
+// #endif
+//
+// // graph_rdwr_op performs operation op on file fname (s and t are
+// // the source and sink of a maximum flow problem).
+// static int graph_rdwr_op(glp_graph *G, int op, int *s, int *t, const char *fname) {
+//	int ret = 1;
+//	switch (op) {
+//	case READ_MINCOST:
+//		ret = glp_read_mincost(G, V_RHS, A_LOW, A_CAP, A_COST, fname);
+//		break;
+//	case WRITE_MINCOST:
+//		ret = glp_write_mincost(G, V_RHS, A_LOW, A_CAP, A_COST, fname);
+//		break;
+//	case READ_MAXFLOW:
+//		ret = glp_read_maxflow(G, s, t, A_CAP, fname);
+//		break;
+//	case WRITE_MAXFLOW:
+//		ret = glp_write_maxflow(G, *s, *t, A_CAP, fname);
+//		break;
+//	case READ_ASNPROB:
+//		ret = glp_read_asnprob(G, -1, A_COST, fname);
+//		break;
+//	case WRITE_ASNPROB:
+//		ret = glp_write_asnprob(G, -1, A_COST, fname);
+//		break;
+//	case READ_CCDATA:
+//		ret = read_ccdata(G, V_WGT, fname);
+//		break;
+//	case WRITE_CCDATA:
+//		ret = write_ccdata(G, V_WGT, fname);
+//		break;
+//	case READ_GRAPH:
+//		ret = glp_read_graph(G, fname);
+//		break;
+//	case WRITE_GRAPH:
+//		ret = glp_write_graph(G, fname);
+//		break;
+//	}
+//	return ret;
+// }
+//
+// GUARDED(int, graph_rdwr, (gcall *c, glp_graph *G, int op, int *s, int *t, const char *fname), graph_rdwr_op(G, op, s, t, fname))
+// GUARDED(int, go_glp_netgen, (gcall *c, glp_graph *G, const int parm[]), glp_netgen(G, V_RHS, A_CAP, A_COST, parm))
+// GUARDED(int, go_glp_gridgen, (gcall *c, glp_graph *G, const int parm[]), glp_gridgen(G, V_RHS, A_CAP, A_COST, parm))
+// GUARDED(int, go_glp_rmfgen, (gcall *c, glp_graph *G, int *s, int *t, const int parm[]), glp_rmfgen(G, s, t, A_CAP, parm))
+import "C"
+
+func (g *Graph) rdwr(op C.int, s, t *int, opname, fname string) error {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	if (op == C.READ_CCDATA || op == C.WRITE_CCDATA) && C.has_ccdata() == 0 {
+		return ErrUnsupported
+	}
+	var s_, t_ C.int
+	if s != nil {
+		s_, t_ = C.int(*s), C.int(*t)
+	}
+	g.g.orderArcs()
+	fname_ := C.CString(fname)
+	defer C.free(unsafe.Pointer(fname_))
+	c := newCall(g.g.w).collect()
+	ret := C.graph_rdwr(&c.c, g.g.g, op, &s_, &t_, fname_)
+	if err := c.err(); err != nil {
+		return err
+	}
+	switch op {
+	case C.READ_MINCOST, C.READ_MAXFLOW, C.READ_ASNPROB, C.READ_CCDATA, C.READ_GRAPH:
+		// the graph is erased before reading (even on failure)
+		g.g.loadArcs()
+	}
+	if ret != 0 {
+		return &FileError{opname, fname, c.out}
+	}
+	if s != nil {
+		*s, *t = int(s_), int(t_)
+	}
+	return nil
+}
+
+// Graph files are read and written with the following methods. The
+// graph is erased before reading, and arcs of the graph read are
+// numbered in order of their tail vertices. If the file name ends
+// with ".gz" it is decompressed (or compressed when writing). On
+// failure an error (a *FileError containing GLPK diagnostics) is
+// returned.
+
+// ReadMinCost reads a minimum cost flow problem in DIMACS format from
+// a file (see SetSupply, SetLow, SetCap and SetCost).
+func (g *Graph) ReadMinCost(fname string) error {
+	return g.rdwr(C.READ_MINCOST, nil, nil, "read DIMACS min-cost", fname)
+}
+
+// WriteMinCost writes a minimum cost flow problem in DIMACS format to
+// a file.
+func (g *Graph) WriteMinCost(fname string) error {
+	return g.rdwr(C.WRITE_MINCOST, nil, nil, "write DIMACS min-cost", fname)
+}
+
+// ReadMaxFlow reads a maximum flow problem in DIMACS format from a
+// file (see SetCap) and returns its source and sink vertices.
+func (g *Graph) ReadMaxFlow(fname string) (s, t int, err error) {
+	err = g.rdwr(C.READ_MAXFLOW, &s, &t, "read DIMACS max-flow", fname)
+	return
+}
+
+// WriteMaxFlow writes a maximum flow problem with source s and sink t
+// in DIMACS format to a file.
+func (g *Graph) WriteMaxFlow(s, t int, fname string) error {
+	return g.rdwr(C.WRITE_MAXFLOW, &s, &t, "write DIMACS max-flow", fname)
+}
+
+// ReadAsnProb reads an assignment problem in DIMACS format from a
+// file (see Assignment and SetCost).
+func (g *Graph) ReadAsnProb(fname string) error {
+	return g.rdwr(C.READ_ASNPROB, nil, nil, "read DIMACS assignment", fname)
+}
+
+// WriteAsnProb writes an assignment problem in DIMACS format to a
+// file. Arcs must go from one set of vertices to the other (see
+// Assignment).
+func (g *Graph) WriteAsnProb(fname string) error {
+	return g.rdwr(C.WRITE_ASNPROB, nil, nil, "write DIMACS assignment", fname)
+}
+
+// ReadCCData reads a graph with vertex weights (see SetWeight) in
+// DIMACS clique/coloring format from a file. As this format describes
+// undirected graphs each edge is read as an arc (from the vertex
+// given first). It returns ErrUnsupported if GLPK is older than 4.47.
+func (g *Graph) ReadCCData(fname string) error {
+	return g.rdwr(C.READ_CCDATA, nil, nil, "read DIMACS clique/coloring", fname)
+}
+
+// WriteCCData writes the graph with vertex weights in DIMACS
+// clique/coloring format to a file (each arc as an edge). It returns
+// ErrUnsupported if GLPK is older than 4.47.
+func (g *Graph) WriteCCData(fname string) error {
+	return g.rdwr(C.WRITE_CCDATA, nil, nil, "write DIMACS clique/coloring", fname)
+}
+
+// ReadGraph reads the graph (without vertex and arc data) in GLPK
+// plain text format from a file.
+func (g *Graph) ReadGraph(fname string) error {
+	return g.rdwr(C.READ_GRAPH, nil, nil, "read graph", fname)
+}
+
+// WriteGraph writes the graph (without vertex and arc data) in GLPK
+// plain text format to a file.
+func (g *Graph) WriteGraph(fname string) error {
+	return g.rdwr(C.WRITE_GRAPH, nil, nil, "write graph", fname)
+}
+
+// ReadMinCostFrom reads a minimum cost flow problem in DIMACS format
+// from r. Gzip compressed data is detected and decompressed. See also
+// ReadMinCost().
+func (g *Graph) ReadMinCostFrom(r io.Reader) error {
+	return readFrom(r, g.ReadMinCost)
+}
+
+// WriteMinCostTo writes a minimum cost flow problem in DIMACS format
+// to w. See also WriteMinCost().
+func (g *Graph) WriteMinCostTo(w io.Writer) error {
+	return writeTo(w, g.WriteMinCost)
+}
+
+// ReadMaxFlowFrom reads a maximum flow problem in DIMACS format from
+// r. Gzip compressed data is detected and decompressed. See also
+// ReadMaxFlow().
+func (g *Graph) ReadMaxFlowFrom(r io.Reader) (s, t int, err error) {
+	err = readFrom(r, func(fname string) (err error) {
+		s, t, err = g.ReadMaxFlow(fname)
+		return err
+	})
+	return
+}
+
+// WriteMaxFlowTo writes a maximum flow problem in DIMACS format to w.
+// See also WriteMaxFlow().
+func (g *Graph) WriteMaxFlowTo(w io.Writer, s, t int) error {
+	return writeTo(w, func(fname string) error { return g.WriteMaxFlow(s, t, fname) })
+}
+
+// ReadAsnProbFrom reads an assignment problem in DIMACS format from
+// r. Gzip compressed data is detected and decompressed. See also
+// ReadAsnProb().
+func (g *Graph) ReadAsnProbFrom(r io.Reader) error {
+	return readFrom(r, g.ReadAsnProb)
+}
+
+// WriteAsnProbTo writes an assignment problem in DIMACS format to w.
+// See also WriteAsnProb().
+func (g *Graph) WriteAsnProbTo(w io.Writer) error {
+	return writeTo(w, g.WriteAsnProb)
+}
+
+// ReadCCDataFrom reads a graph in DIMACS clique/coloring format from
+// r. Gzip compressed data is detected and decompressed. See also
+// ReadCCData().
+func (g *Graph) ReadCCDataFrom(r io.Reader) error {
+	return readFrom(r, g.ReadCCData)
+}
+
+// WriteCCDataTo writes the graph in DIMACS clique/coloring format to
+// w. See also WriteCCData().
+func (g *Graph) WriteCCDataTo(w io.Writer) error {
+	return writeTo(w, g.WriteCCData)
+}
+
+// ReadGraphFrom reads the graph in GLPK plain text format from r.
+// Gzip compressed data is detected and decompressed. See also
+// ReadGraph().
+func (g *Graph) ReadGraphFrom(r io.Reader) error {
+	return readFrom(r, g.ReadGraph)
+}
+
+// WriteGraphTo writes the graph in GLPK plain text format to w. See
+// also WriteGraph().
+func (g *Graph) WriteGraphTo(w io.Writer) error {
+	return writeTo(w, g.WriteGraph)
+}
+
+// NetgenParm contains parameters of the NETGEN generator of network
+// problems (see Graph.Netgen).
+type NetgenParm struct {
+	Seed        int // random number seed (8-digit positive integer)
+	Prob        int // problem number (8-digit positive integer)
+	Nodes       int // total number of vertices
+	Sources     int // number of source vertices (including transshipment ones)
+	Sinks       int // number of sink vertices (including transshipment ones)
+	Arcs        int // number of arcs
+	MinCost     int // minimal arc cost
+	MaxCost     int // maximal arc cost
+	Supply      int // total supply
+	TSources    int // number of transshipment source vertices
+	TSinks      int // number of transshipment sink vertices
+	HiCost      int // percentage of skeleton arcs given the maximal cost
+	Capacitated int // percentage of arcs to be capacitated
+	MinCap      int // minimal arc capacity
+	MaxCap      int // maximal arc capacity
+}
+
+// Netgen erases the graph and fills it with a minimum cost flow
+// problem (see MinCost) generated by the NETGEN generator of Klingman,
+// Napier and Stutz. An error is returned if the parameters are
+// invalid.
+func (g *Graph) Netgen(parm *NetgenParm) error {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	parm_ := [1 + 15]C.int{0,
+		C.int(parm.Seed), C.int(parm.Prob), C.int(parm.Nodes), C.int(parm.Sources),
+		C.int(parm.Sinks), C.int(parm.Arcs), C.int(parm.MinCost), C.int(parm.MaxCost),
+		C.int(parm.Supply), C.int(parm.TSources), C.int(parm.TSinks), C.int(parm.HiCost),
+		C.int(parm.Capacitated), C.int(parm.MinCap), C.int(parm.MaxCap)}
+	c := newCall(g.g.w)
+	ret := C.go_glp_netgen(&c.c, g.g.g, &parm_[0])
+	if err := c.err(); err != nil {
+		return err
+	}
+	g.g.loadArcs()
+	if ret != 0 {
+		return fmt.Errorf("glpk: Netgen: invalid parameters")
+	}
+	return nil
+}
+
+// Distribution of random values generated by GRIDGEN
+type GridgenDist int
+
+const (
+	GRID_UNIFORM = GridgenDist(1) // uniform distribution on [Min, Max]
+	GRID_EXP     = GridgenDist(2) // exponential distribution with rate Min/100
+)
+
+// GridgenParm contains parameters of the GRIDGEN generator of network
+// problems (see Graph.Gridgen).
+type GridgenParm struct {
+	TwoWay   bool        // whether arcs of the grid go in both directions
+	Seed     int         // random number seed
+	Nodes    int         // total number of vertices
+	Width    int         // width of the grid
+	Sources  int         // number of source vertices
+	Sinks    int         // number of sink vertices
+	Degree   int         // average degree of vertices
+	Flow     int         // total flow
+	CostDist GridgenDist // distribution of arc costs
+	MinCost  int         // minimal arc cost (or 100 times the rate of the distribution)
+	MaxCost  int         // maximal arc cost (unused for GRID_EXP)
+	CapDist  GridgenDist // distribution of arc capacities
+	MinCap   int         // minimal arc capacity (or 100 times the rate of the distribution)
+	MaxCap   int         // maximal arc capacity (unused for GRID_EXP)
+}
+
+// Gridgen erases the graph and fills it with a grid-like minimum cost
+// flow problem (see MinCost) generated by the GRIDGEN generator of
+// Bertsekas. An error is returned if the parameters are invalid.
+func (g *Graph) Gridgen(parm *GridgenParm) error {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	twoWay := 0
+	if parm.TwoWay {
+		twoWay = 1
+	}
+	parm_ := [1 + 14]C.int{0,
+		C.int(twoWay), C.int(parm.Seed), C.int(parm.Nodes), C.int(parm.Width),
+		C.int(parm.Sources), C.int(parm.Sinks), C.int(parm.Degree), C.int(parm.Flow),
+		C.int(parm.CostDist), C.int(parm.MinCost), C.int(parm.MaxCost),
+		C.int(parm.CapDist), C.int(parm.MinCap), C.int(parm.MaxCap)}
+	c := newCall(g.g.w)
+	ret := C.go_glp_gridgen(&c.c, g.g.g, &parm_[0])
+	if err := c.err(); err != nil {
+		return err
+	}
+	g.g.loadArcs()
+	if ret != 0 {
+		return fmt.Errorf("glpk: Gridgen: invalid parameters")
+	}
+	return nil
+}
+
+// RmfgenParm contains parameters of the RMFGEN generator of maximum
+// flow problems (see Graph.Rmfgen). The network consists of Depth
+// square grids (frames) of Side x Side vertices.
+type RmfgenParm struct {
+	Seed   int // random number seed
+	Side   int // side of a frame
+	Depth  int // number of frames
+	MinCap int // minimal capacity of arcs between frames
+	MaxCap int // maximal capacity of arcs between frames
+}
+
+// Rmfgen erases the graph and fills it with a maximum flow problem
+// (see MaxFlow) generated by the RMFGEN generator of Goldfarb and
+// Grigoriadis. It returns the source and sink vertices. An error is
+// returned if the parameters are invalid.
+func (g *Graph) Rmfgen(parm *RmfgenParm) (s, t int, err error) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	parm_ := [1 + 5]C.int{0, C.int(parm.Seed), C.int(parm.Side), C.int(parm.Depth), C.int(parm.MinCap), C.int(parm.MaxCap)}
+	var s_, t_ C.int
+	c := newCall(g.g.w)
+	ret := C.go_glp_rmfgen(&c.c, g.g.g, &s_, &t_, &parm_[0])
+	if err := c.err(); err != nil {
+		return 0, 0, err
+	}
+	g.g.loadArcs()
+	if ret != 0 {
+		return 0, 0, fmt.Errorf("glpk: Rmfgen: invalid parameters")
+	}
+	return int(s_), int(t_), nil
+}
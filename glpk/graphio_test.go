@@ -0,0 +1,227 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+)
+
+const maxFlowDIMACS = `c sample maximum flow problem
+p max 4 5
+n 1 s
+n 4 t
+a 1 2 3
+a 1 3 2
+a 2 3 1
+a 2 4 2
+a 3 4 3
+`
+
+func checkSameGraph(t *testing.T, g, g2 *Graph) {
+	if g.NumVertices() != g2.NumVertices() || g.NumArcs() != g2.NumArcs() {
+		t.Fatalf("Got graph with %d vertices and %d arcs expected %d and %d", g2.NumVertices(), g2.NumArcs(), g.NumVertices(), g.NumArcs())
+	}
+	for a := 1; a <= g.NumArcs(); a++ {
+		i, j := g.Arc(a)
+		i2, j2 := g2.Arc(a)
+		if i != i2 || j != j2 {
+			t.Errorf("Arc %d is (%d, %d) expected (%d, %d)", a, i2, j2, i, j)
+		}
+	}
+}
+
+func TestReadMaxFlow(t *testing.T) {
+	g := NewGraph()
+	defer g.Delete()
+	g.SetTermWriter(ioutil.Discard)
+	s, tt, err := g.ReadMaxFlowFrom(strings.NewReader(maxFlowDIMACS))
+	if err != nil {
+		t.Fatalf("ReadMaxFlowFrom error: %v", err)
+	}
+	if s != 1 || tt != 4 {
+		t.Errorf("Got source %d and sink %d expected 1 and 4", s, tt)
+	}
+	net := networkGraph()
+	defer net.Delete()
+	checkSameGraph(t, net, g)
+	for a := 1; a <= g.NumArcs(); a++ {
+		if g.Cap(a) != net.Cap(a) {
+			t.Errorf("Arc %d has capacity %g expected %g", a, g.Cap(a), net.Cap(a))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := g.WriteMaxFlowTo(&buf, 1, 4); err != nil {
+		t.Fatalf("WriteMaxFlowTo error: %v", err)
+	}
+	g2 := NewGraph()
+	defer g2.Delete()
+	g2.SetTermWriter(ioutil.Discard)
+	if s, tt, err := g2.ReadMaxFlowFrom(&buf); err != nil || s != 1 || tt != 4 {
+		t.Fatalf("ReadMaxFlowFrom returned %d, %d, %v", s, tt, err)
+	}
+	checkSameGraph(t, g, g2)
+
+	_, _, err = g.ReadMaxFlowFrom(strings.NewReader("p max x\n"))
+	if _, ok := err.(*FileError); !ok {
+		t.Errorf("Expected *FileError for invalid data got %v", err)
+	}
+	if g.NumVertices() != 0 || g.NumArcs() != 0 {
+		t.Errorf("Got %d vertices and %d arcs after failed read", g.NumVertices(), g.NumArcs())
+	}
+}
+
+func TestReadWriteGraph(t *testing.T) {
+	g := networkGraph()
+	defer g.Delete()
+	g.SetSupply(1, 4)
+	g.SetSupply(4, -4)
+	for i := 1; i <= 4; i++ {
+		g.SetWeight(i, float64(i))
+	}
+	for _, tc := range []struct {
+		name  string
+		write func(g *Graph, buf *bytes.Buffer) error
+		read  func(g *Graph, buf *bytes.Buffer) error
+	}{
+		{"min-cost", func(g *Graph, buf *bytes.Buffer) error { return g.WriteMinCostTo(buf) },
+			func(g *Graph, buf *bytes.Buffer) error { return g.ReadMinCostFrom(buf) }},
+		{"graph", func(g *Graph, buf *bytes.Buffer) error { return g.WriteGraphTo(buf) },
+			func(g *Graph, buf *bytes.Buffer) error { return g.ReadGraphFrom(buf) }},
+		{"clique", func(g *Graph, buf *bytes.Buffer) error { return g.WriteCCDataTo(buf) },
+			func(g *Graph, buf *bytes.Buffer) error { return g.ReadCCDataFrom(buf) }},
+	} {
+		var buf bytes.Buffer
+		if err := tc.write(g, &buf); err == ErrUnsupported {
+			t.Logf("Writing %s format is not supported", tc.name)
+			continue
+		} else if err != nil {
+			t.Fatalf("Writing %s format failed: %v", tc.name, err)
+		}
+		g2 := NewGraph()
+		g2.SetTermWriter(ioutil.Discard)
+		if err := tc.read(g2, &buf); err != nil {
+			t.Fatalf("Reading %s format failed: %v", tc.name, err)
+		}
+		checkSameGraph(t, g, g2)
+		switch tc.name {
+		case "min-cost":
+			if sol, _, _, err := g2.MinCost(); err != nil || sol != 16 {
+				t.Errorf("MinCost of the read problem returned %g, %v expected 16", sol, err)
+			}
+		case "clique":
+			if w := g2.Weight(3); w != 3 {
+				t.Errorf("Vertex 3 has weight %g expected 3", w)
+			}
+		}
+		g2.Delete()
+	}
+
+	a := asnGraph()
+	defer a.Delete()
+	var buf bytes.Buffer
+	if err := a.WriteAsnProbTo(&buf); err != nil {
+		t.Fatalf("WriteAsnProbTo error: %v", err)
+	}
+	a2 := NewGraph()
+	defer a2.Delete()
+	a2.SetTermWriter(ioutil.Discard)
+	if err := a2.ReadAsnProbFrom(&buf); err != nil {
+		t.Fatalf("ReadAsnProbFrom error: %v", err)
+	}
+	checkSameGraph(t, a, a2)
+	if sol, _, err := a2.Assignment(ASN_MIN); err != nil || sol != 5 {
+		t.Errorf("Assignment of the read problem returned %g, %v expected 5", sol, err)
+	}
+}
+
+// lpObjVal solves the LP and returns the objective value.
+func lpObjVal(t *testing.T, lp *Prob, err error) float64 {
+	if err != nil {
+		t.Fatalf("Converting to LP failed: %v", err)
+	}
+	defer lp.Delete()
+	lp.SetTermWriter(ioutil.Discard)
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	return lp.ObjVal()
+}
+
+func TestNetgen(t *testing.T) {
+	g := NewGraph()
+	defer g.Delete()
+	g.SetTermWriter(ioutil.Discard)
+	parm := &NetgenParm{Seed: 13502460, Prob: 101, Nodes: 100, Sources: 10, Sinks: 10,
+		Arcs: 500, MinCost: 1, MaxCost: 100, Supply: 1000, HiCost: 10,
+		Capacitated: 100, MinCap: 100, MaxCap: 1000}
+	if err := g.Netgen(parm); err != nil {
+		t.Fatalf("Netgen error: %v", err)
+	}
+	if g.NumVertices() != 100 || g.NumArcs() == 0 {
+		t.Fatalf("Got graph with %d vertices and %d arcs", g.NumVertices(), g.NumArcs())
+	}
+	sol, _, _, err := g.MinCost()
+	if err != nil {
+		t.Fatalf("MinCost error: %v", err)
+	}
+	lp, _, err := g.MinCostLP(false)
+	if obj := lpObjVal(t, lp, err); math.Abs(obj-sol) > 1e-6 {
+		t.Errorf("MinCost = %g but LP objective is %g", sol, obj)
+	}
+}
+
+func TestGridgen(t *testing.T) {
+	g := NewGraph()
+	defer g.Delete()
+	g.SetTermWriter(ioutil.Discard)
+	parm := &GridgenParm{Seed: 123, Nodes: 100, Width: 10, Sources: 5, Sinks: 5,
+		Degree: 4, Flow: 500, CostDist: GRID_UNIFORM, MinCost: 1, MaxCost: 100,
+		CapDist: GRID_UNIFORM, MinCap: 100, MaxCap: 1000}
+	if err := g.Gridgen(parm); err != nil {
+		t.Fatalf("Gridgen error: %v", err)
+	}
+	if g.NumVertices() < 100 || g.NumArcs() == 0 {
+		t.Errorf("Got graph with %d vertices and %d arcs", g.NumVertices(), g.NumArcs())
+	}
+}
+
+func TestRmfgen(t *testing.T) {
+	g := NewGraph()
+	defer g.Delete()
+	g.SetTermWriter(ioutil.Discard)
+	s, tt, err := g.Rmfgen(&RmfgenParm{Seed: 123, Side: 3, Depth: 4, MinCap: 1, MaxCap: 100})
+	if err != nil {
+		t.Fatalf("Rmfgen error: %v", err)
+	}
+	if g.NumVertices() != 36 || s == tt {
+		t.Fatalf("Got graph with %d vertices, source %d and sink %d", g.NumVertices(), s, tt)
+	}
+	sol, _, _, err := g.MaxFlow(s, tt)
+	if err != nil {
+		t.Fatalf("MaxFlow error: %v", err)
+	}
+	lp, _, err := g.MaxFlowLP(s, tt, false)
+	if obj := lpObjVal(t, lp, err); sol <= 0 || math.Abs(obj-sol) > 1e-6 {
+		t.Errorf("MaxFlow = %g but LP objective is %g", sol, obj)
+	}
+}
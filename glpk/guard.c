@@ -0,0 +1,216 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+#include <pthread.h>
+#include <stdlib.h>
+#include <string.h>
+#include <glpk.h>
+#include "guard.h"
+
+// current is the innermost guarded call of the current thread.
+static __thread guard *current;
+
+// failed is set when a fatal error occurred in a nested guarded call
+// (e.g. in a callback called by GLPK) and fatal is its message. The
+// environment can not be freed until the control returns to the C
+// code of the enclosing guarded call (see guard_unwind) so until then
+// guarded calls fail immediately.
+static __thread int failed;
+static __thread char *fatal;
+
+static char *fatal_msg(void) {
+	return fatal != NULL ? strdup(fatal) : NULL;
+}
+
+// env is the id of the GLPK environment of the current thread (0 if
+// not assigned yet) and last_env is the last assigned id.
+static __thread uint64_t env;
+static uint64_t last_env;
+
+uint64_t guard_env(void) {
+	if (env == 0)
+		env = __atomic_add_fetch(&last_env, 1, __ATOMIC_RELAXED);
+	return env;
+}
+
+// pending is the list of objects waiting to be freed in their own
+// environments (see guard_free). npending is its length which is
+// read without locking to avoid taking the mutex on every call.
+typedef struct pending_free {
+	int kind;
+	void *obj;
+	uint64_t env;
+	struct pending_free *next;
+} pending_free;
+
+static pthread_mutex_t pending_mu = PTHREAD_MUTEX_INITIALIZER;
+static pending_free *pending;
+static int npending;
+
+static void free_obj(int kind, void *obj) {
+	switch (kind) {
+	case GUARD_PROB:
+		glp_delete_prob(obj);
+		break;
+	case GUARD_TRAN:
+		glp_mpl_free_wksp(obj);
+		break;
+	case GUARD_GRAPH:
+		glp_delete_graph(obj);
+		break;
+	}
+}
+
+// take_pending removes from the pending list the objects of
+// environment id and returns them.
+static pending_free *take_pending(uint64_t id) {
+	pending_free *taken = NULL, **p, *f;
+	if (__atomic_load_n(&npending, __ATOMIC_RELAXED) == 0)
+		return NULL;
+	pthread_mutex_lock(&pending_mu);
+	for (p = &pending; (f = *p) != NULL;) {
+		if (f->env == id) {
+			*p = f->next;
+			f->next = taken;
+			taken = f;
+			__atomic_sub_fetch(&npending, 1, __ATOMIC_RELAXED);
+		} else {
+			p = &f->next;
+		}
+	}
+	pthread_mutex_unlock(&pending_mu);
+	return taken;
+}
+
+// free_pending frees objects queued for the environment of the
+// current thread.
+static void free_pending(void) {
+	pending_free *f = take_pending(guard_env()), *next;
+	for (; f != NULL; f = next) {
+		next = f->next;
+		free_obj(f->kind, f->obj);
+		free(f);
+	}
+}
+
+// drop_pending forgets objects of environment id (which was freed
+// along with them).
+static void drop_pending(uint64_t id) {
+	pending_free *f = take_pending(id), *next;
+	for (; f != NULL; f = next) {
+		next = f->next;
+		free(f);
+	}
+}
+
+void guard_free(int kind, void *obj, uint64_t id) {
+	pending_free *f;
+	if (id == guard_env()) {
+		free_obj(kind, obj);
+		return;
+	}
+	f = malloc(sizeof(*f));
+	if (f == NULL)
+		return; // the object leaks
+	f->kind = kind;
+	f->obj = obj;
+	f->env = id;
+	pthread_mutex_lock(&pending_mu);
+	f->next = pending;
+	pending = f;
+	__atomic_add_fetch(&npending, 1, __ATOMIC_RELAXED);
+	pthread_mutex_unlock(&pending_mu);
+}
+
+static void error_hook(void *info) {
+	guard *g = info;
+	longjmp(g->jb, 1);
+}
+
+int guard_begin(guard *g, gcall *c) {
+	if (failed) {
+		c->failed = 1;
+		c->err = fatal_msg();
+		c->freed = guard_env();
+		return 0;
+	}
+	if (current == NULL)
+		free_pending();
+	g->prev = current;
+	current = g;
+	// the output is collected to return the message of a fatal error
+	term_begin(&g->term, c->cfg, c->collect ? TERM_COLLECT : TERM_COLLECT_TAIL);
+	glp_error_hook(error_hook, g);
+	return 1;
+}
+
+// guard_pop makes the enclosing guarded call (if any) current again.
+static void guard_pop(guard *g) {
+	current = g->prev;
+	if (current != NULL) {
+		term_resume(&current->term);
+		glp_error_hook(error_hook, current);
+	} else {
+		glp_error_hook(NULL, NULL);
+	}
+}
+
+void guard_end(guard *g, gcall *c) {
+	char *out = term_end(&g->term);
+	guard_pop(g);
+	if (c->collect)
+		c->out = out;
+	else
+		free(out);
+}
+
+void guard_fail(guard *g, gcall *c) {
+	c->failed = 1;
+	if (!failed) {
+		c->err = g->term.buf.buf;
+		if (g->prev != NULL) {
+			failed = 1;
+			fatal = c->err != NULL ? strdup(c->err) : NULL;
+		}
+	} else {
+		// unwinding after a nested failure (see guard_unwind)
+		free(g->term.buf.buf);
+		c->err = fatal_msg();
+	}
+	c->freed = guard_env();
+	if (g->prev == NULL) {
+		// glp_free_env also removes the hooks
+		current = NULL;
+		glp_free_env();
+		env = 0;
+		drop_pending(c->freed);
+		failed = 0;
+		free(fatal);
+		fatal = NULL;
+	} else {
+		guard_pop(g);
+	}
+}
+
+int guard_failed(void) {
+	return failed;
+}
+
+void guard_unwind(void) {
+	if (failed && current != NULL)
+		longjmp(current->jb, 1);
+}
@@ -0,0 +1,154 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// #include <stdlib.h>
+// #include "guard.h"
+import "C"
+
+// GLPK routines are called through wrappers defined with GUARDED (see
+// guard.h) which recover from GLPK fatal errors (see the package
+// documentation).
+
+// GLPK keeps its environment (including the memory of problems,
+// translators and graphs) per thread. Objects record the id of the
+// environment they were created in (see guard_env in guard.h) and
+// become invalid once it is freed after a fatal error. The id has to
+// be obtained in the same C call which creates the object as the
+// goroutine may move to another thread between cgo calls.
+
+// deadEnvs is the set of ids of freed GLPK environments and anyDead
+// tells (atomically) whether it is not empty so that objects may be
+// checked without locking until the first fatal error.
+var (
+	deadEnvs sync.Map
+	anyDead  uint32
+)
+
+// envDead reports whether GLPK environment env was freed.
+func envDead(env uint64) bool {
+	if atomic.LoadUint32(&anyDead) == 0 {
+		return false
+	}
+	_, ok := deadEnvs.Load(env)
+	return ok
+}
+
+func setEnvDead(env uint64) {
+	deadEnvs.Store(env, true)
+	atomic.StoreUint32(&anyDead, 1)
+}
+
+// FatalError is a GLPK fatal error which would otherwise abort the
+// program.
+type FatalError struct {
+	Msg  string // error message printed by GLPK
+	File string // GLPK source file in which the error was detected
+	Line int    // line number in File
+}
+
+func (e *FatalError) Error() string {
+	return "glpk: fatal error: " + e.Msg
+}
+
+var fatalErrorRe = regexp.MustCompile(`^Error detected in file (.*) at line ([0-9]+)$`)
+
+// parseFatalError extracts the error message from the terminal output
+// of the failed call.
+func parseFatalError(out string) *FatalError {
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		m := fatalErrorRe.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		e := &FatalError{File: m[1]}
+		e.Line, _ = strconv.Atoi(m[2])
+		if i > 0 {
+			e.Msg = strings.TrimSpace(lines[i-1])
+		}
+		if e.Msg == "" {
+			e.Msg = "unknown error"
+		}
+		return e
+	}
+	if msg := strings.TrimSpace(lines[len(lines)-1]); msg != "" {
+		return &FatalError{Msg: msg}
+	}
+	return &FatalError{Msg: "unknown error"}
+}
+
+// gcall represents a single call of a guarded GLPK routine, i.e., a C
+// function defined with GUARDED or GUARDED_VOID (see guard.h).
+type gcall struct {
+	c   C.gcall
+	out string // collected terminal output (if requested)
+}
+
+// newCall prepares a guarded call with terminal output going to w
+// (see termConfig()).
+func newCall(w termWriter) *gcall {
+	c := new(gcall)
+	c.c.cfg = termConfig(w)
+	return c
+}
+
+// collect requests collecting terminal output of the call in c.out.
+func (c *gcall) collect() *gcall {
+	c.c.collect = 1
+	return c
+}
+
+// err should be called after the call. It returns *FatalError if a
+// fatal error occurred.
+func (c *gcall) err() error {
+	if c.c.out != nil {
+		c.out = C.GoString(c.c.out)
+		C.free(unsafe.Pointer(c.c.out))
+		c.c.out = nil
+	}
+	if c.c.failed == 0 {
+		return nil
+	}
+	var out string
+	if c.c.err != nil {
+		out = C.GoString(c.c.err)
+		C.free(unsafe.Pointer(c.c.err))
+		c.c.err = nil
+	}
+	if c.c.freed != 0 {
+		setEnvDead(uint64(c.c.freed))
+	}
+	return parseFatalError(out)
+}
+
+// done is like err but it panics with *FatalError.
+func (c *gcall) done() {
+	if err := c.err(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,117 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+#ifndef GO_GLPK_GUARD_H
+#define GO_GLPK_GUARD_H
+
+#include <setjmp.h>
+#include <stdint.h>
+#include "term.h"
+
+// GLPK_AT_LEAST tells whether the GLPK headers are of at least the
+// given version (glpk.h has to be included before using it).
+#define GLPK_AT_LEAST(major, minor) \
+	(GLP_MAJOR_VERSION > (major) || \
+	 (GLP_MAJOR_VERSION == (major) && GLP_MINOR_VERSION >= (minor)))
+
+// gcall describes a single call of a guarded GLPK routine (see
+// GUARDED below). It is allocated by Go.
+typedef struct {
+	termcfg cfg; // terminal output configuration
+	int collect; // whether to return terminal output in out
+	char *out;   // terminal output (if collect is set)
+	int failed;     // whether a GLPK fatal error occurred
+	uint64_t freed; // id of the GLPK environment freed due to it or 0
+	char *err;      // terminal output if a GLPK fatal error occurred
+} gcall;
+
+// guard is the state of a guarded call. Guarded calls may be nested
+// (e.g. in callbacks called by GLPK).
+typedef struct guard {
+	jmp_buf jb;
+	termctx term;
+	struct guard *prev;
+} guard;
+
+// guard_env returns the id of the GLPK environment of the calling
+// thread. The environment gets a new id once it is freed after a
+// fatal error.
+uint64_t guard_env(void);
+
+// Kinds of GLPK objects freed with guard_free.
+enum { GUARD_PROB, GUARD_TRAN, GUARD_GRAPH };
+
+// guard_free frees a GLPK object of the given kind which belongs to
+// environment env. Memory of an object has to be freed in its own
+// environment so if env is not the environment of the calling thread
+// (e.g. when called by a finalizer) the object is freed by the next
+// guarded call of the thread which created it.
+void guard_free(int kind, void *obj, uint64_t env);
+
+int guard_begin(guard *g, gcall *c);
+void guard_end(guard *g, gcall *c);
+void guard_fail(guard *g, gcall *c);
+
+// guard_failed tells whether a nested guarded call of the current
+// thread failed (see GUARDED) and GLPK has to be left.
+int guard_failed(void);
+
+// guard_unwind should be called by C code called by GLPK (such as a
+// callback) after calling Go code. If a nested guarded call failed it
+// long jumps to the innermost guarded call which then fails too.
+void guard_unwind(void);
+
+// GUARDED defines function name with parameters params (the first of
+// which should be gcall *c) which evaluates call with GLPK error hook
+// installed. On a GLPK fatal error (which would otherwise abort the
+// program) the hook long jumps back so that the function returns 0
+// with c->failed set. As GLPK is in undefined state after such error
+// the GLPK environment of the current thread is freed with
+// glp_free_env (and its id is stored in c->freed). If the call was
+// nested in another guarded call (e.g. made by a callback called by
+// GLPK) the environment is freed when the enclosing call fails too
+// (see guard_unwind) and until then guarded calls fail immediately.
+#define GUARDED(type, name, params, call) \
+	static type name params { \
+		guard g; \
+		type ret; \
+		if (!guard_begin(&g, c)) \
+			return 0; \
+		if (setjmp(g.jb)) { \
+			guard_fail(&g, c); \
+			return 0; \
+		} \
+		ret = call; \
+		guard_end(&g, c); \
+		return ret; \
+	}
+
+// GUARDED_VOID is like GUARDED but for calls without result.
+#define GUARDED_VOID(name, params, call) \
+	static void name params { \
+		guard g; \
+		if (!guard_begin(&g, c)) \
+			return; \
+		if (setjmp(g.jb)) { \
+			guard_fail(&g, c); \
+			return; \
+		} \
+		call; \
+		guard_end(&g, c); \
+	}
+
+#endif
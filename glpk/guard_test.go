@@ -0,0 +1,148 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"runtime"
+	"testing"
+)
+
+// fatal calls f and returns the value it panicked with.
+func fatal(f func()) (v interface{}) {
+	defer func() {
+		v = recover()
+	}()
+	f()
+	return nil
+}
+
+func TestFatalError(t *testing.T) {
+	// the fatal error frees the GLPK environment of the thread which
+	// created lp
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	lp := sampleProb()
+	lp.SetTermWriter(ioutil.Discard)
+	v := fatal(func() { lp.SetRowBnds(10, UP, 0, 1) })
+	e, ok := v.(*FatalError)
+	if !ok {
+		t.Fatalf("expected *FatalError panic but got %#v", v)
+	}
+	if e.Msg == "" || e.File == "" || e.Line == 0 {
+		t.Errorf("incomplete FatalError: %#v", e)
+	}
+
+	// lp was freed along with the GLPK environment
+	if v := fatal(func() { lp.NumRows() }); v == nil {
+		t.Errorf("expected panic when using an invalidated problem")
+	}
+	lp.Delete()
+
+	// but GLPK is still usable
+	lp = sampleProb()
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	CheckClose(t, lp.ObjVal(), 733.33333333333333)
+	lp.Delete()
+}
+
+func TestGetterIndexError(t *testing.T) {
+	lp := sampleProb()
+	defer lp.Delete()
+	v := fatal(func() { lp.RowLB(10) })
+	if e, ok := v.(*IndexError); !ok || e.Op != "RowLB" || e.Index != 10 || e.Max != 3 {
+		t.Errorf("expected *IndexError panic but got %#v", v)
+	}
+	if _, ok := fatal(func() { lp.MatCol(0) }).(*IndexError); !ok {
+		t.Errorf("expected *IndexError panic")
+	}
+	// no fatal error occurred so lp is still valid
+	CheckClose(t, lp.ObjCoef(1), 10)
+}
+
+func TestFatalErrorReturned(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	lp := sampleProb()
+	lp.SetTermWriter(ioutil.Discard)
+	iocp := NewIocp()
+	iocp.SetTmLim(-1) // invalid
+	err := lp.Intopt(iocp)
+	if _, ok := err.(*FatalError); !ok {
+		t.Errorf("expected *FatalError but got %#v", err)
+	}
+	lp.Delete()
+}
+
+func TestFatalErrorOtherThread(t *testing.T) {
+	// a problem solved repeatedly on another thread is not affected
+	// by fatal errors freeing the GLPK environment of this thread
+	stop := make(chan struct{})
+	errc := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		lp := sampleProb()
+		lp.SetTermWriter(ioutil.Discard)
+		defer lp.Delete()
+		for {
+			select {
+			case <-stop:
+				errc <- nil
+				return
+			default:
+			}
+			var err error
+			if v := fatal(func() {
+				lp.StdBasis()
+				err = lp.Simplex(nil)
+			}); v != nil {
+				err = fmt.Errorf("panic: %v", v)
+			} else if err == nil && math.Abs(lp.ObjVal()-733.33333333333333) > 1e-6 {
+				err = errors.New("wrong objective value")
+			}
+			if err != nil {
+				errc <- err
+				return
+			}
+		}
+	}()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	for i := 0; i < 20; i++ {
+		lp := sampleProb()
+		lp.SetTermWriter(ioutil.Discard)
+		if _, ok := fatal(func() { lp.SetRowBnds(10, UP, 0, 1) }).(*FatalError); !ok {
+			t.Errorf("expected *FatalError panic")
+			break
+		}
+		if !lp.p.invalid() {
+			t.Errorf("problem not invalidated by a fatal error on its thread")
+		}
+	}
+	close(stop)
+	if err := <-errc; err != nil {
+		t.Errorf("problem on another thread: %v", err)
+	}
+}
@@ -0,0 +1,501 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// #include <glpk.h>
+// #include <stdlib.h>
+// #include "guard.h"
+//
+// enum {
+//	READ_MPS, WRITE_MPS, READ_LP, WRITE_LP, READ_PROB, WRITE_PROB, READ_CNFSAT, WRITE_CNFSAT,
+//	PRINT_SOL, READ_SOL, WRITE_SOL, PRINT_IPT, READ_IPT, WRITE_IPT, PRINT_MIP, READ_MIP, WRITE_MIP
+// };
+//
+// #if GLPK_AT_LEAST(4, 47)
+// static int has_cnfsat(void) { return 1; }
+// #define read_cnfsat glp_read_cnfsat
+// #define write_cnfsat glp_write_cnfsat
+// #else
+// static int has_cnfsat(void) { return 0; }
+// #define read_cnfsat(P, fname) 1
+// #define write_cnfsat(P, fname) 1
+// #endif
+//
+// // rdwr_op performs operation op on file fname.
+// static int rdwr_op(glp_prob *P, int op, int fmt, const char *fname) {
+//	int ret = 1;
+//	switch (op) {
+//	case READ_MPS:
+//		ret = glp_read_mps(P, fmt, NULL, fname);
+//		break;
+//	case WRITE_MPS:
+//		ret = glp_write_mps(P, fmt, NULL, fname);
+//		break;
+//	case READ_LP:
+//		ret = glp_read_lp(P, NULL, fname);
+//		break;
+//	case WRITE_LP:
+//		ret = glp_write_lp(P, NULL, fname);
+//		break;
+//	case READ_PROB:
+//		ret = glp_read_prob(P, 0, fname);
+//		break;
+//	case WRITE_PROB:
+//		ret = glp_write_prob(P, 0, fname);
+//		break;
+//	case READ_CNFSAT:
+//		ret = read_cnfsat(P, fname);
+//		break;
+//	case WRITE_CNFSAT:
+//		ret = write_cnfsat(P, fname);
+//		break;
+//	case PRINT_SOL:
+//		ret = glp_print_sol(P, fname);
+//		break;
+//	case READ_SOL:
+//		ret = glp_read_sol(P, fname);
+//		break;
+//	case WRITE_SOL:
+//		ret = glp_write_sol(P, fname);
+//		break;
+//	case PRINT_IPT:
+//		ret = glp_print_ipt(P, fname);
+//		break;
+//	case READ_IPT:
+//		ret = glp_read_ipt(P, fname);
+//		break;
+//	case WRITE_IPT:
+//		ret = glp_write_ipt(P, fname);
+//		break;
+//	case PRINT_MIP:
+//		ret = glp_print_mip(P, fname);
+//		break;
+//	case READ_MIP:
+//		ret = glp_read_mip(P, fname);
+//		break;
+//	case WRITE_MIP:
+//		ret = glp_write_mip(P, fname);
+//		break;
+//	}
+//	return ret;
+// }
+//
+// GUARDED(int, rdwr, (gcall *c, glp_prob *P, int op, int fmt, const char *fname), rdwr_op(P, op, fmt, fname))
+import "C"
+
+// FileError is returned when GLPK fails to read or write a file.
+type FileError struct {
+	Op   string // operation, e.g. "read MPS"
+	Name string // file name
+	Msg  string // diagnostic messages printed by GLPK
+}
+
+func (e *FileError) Error() string {
+	msg := strings.Replace(strings.TrimSpace(e.Msg), "\n", "; ", -1)
+	if msg == "" {
+		return "glpk: " + e.Op + " " + e.Name + ": failed"
+	}
+	return "glpk: " + e.Op + " " + e.Name + ": " + msg
+}
+
+// MPS file format
+type MPSFormat int
+
+const (
+	MPS_DECK = MPSFormat(C.GLP_MPS_DECK) // fixed (ancient) MPS format
+	MPS_FILE = MPSFormat(C.GLP_MPS_FILE) // free (modern) MPS format
+)
+
+func (p *Prob) rdwr(op C.int, format MPSFormat, opname, fname string) error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if (op == C.READ_CNFSAT || op == C.WRITE_CNFSAT) && C.has_cnfsat() == 0 {
+		return ErrUnsupported
+	}
+	s := C.CString(fname)
+	defer C.free(unsafe.Pointer(s))
+	c := newCall(p.p.w).collect()
+	ret := C.rdwr(&c.c, p.p.p, op, C.int(format), s)
+	if err := c.err(); err != nil {
+		return err
+	}
+	if ret != 0 {
+		return &FileError{opname, fname, c.out}
+	}
+	return nil
+}
+
+// ReadMPS reads problem data in fixed (glpk.MPS_DECK) or free
+// (glpk.MPS_FILE) MPS format from a file. The problem is erased before
+// reading. If the file name ends with ".gz" it is decompressed. On
+// failure an error (a *FileError containing GLPK diagnostics) is
+// returned.
+func (p *Prob) ReadMPS(format MPSFormat, fname string) error {
+	return p.rdwr(C.READ_MPS, format, "read MPS", fname)
+}
+
+// WriteMPS writes problem data in fixed (glpk.MPS_DECK) or free
+// (glpk.MPS_FILE) MPS format to a file. If the file name ends with
+// ".gz" it is compressed. On failure an error (a *FileError) is
+// returned.
+func (p *Prob) WriteMPS(format MPSFormat, fname string) error {
+	return p.rdwr(C.WRITE_MPS, format, "write MPS", fname)
+}
+
+// ReadLP reads problem data in CPLEX LP format from a file. The
+// problem is erased before reading. If the file name ends with ".gz"
+// it is decompressed. On failure an error (a *FileError containing
+// GLPK diagnostics) is returned.
+func (p *Prob) ReadLP(fname string) error {
+	return p.rdwr(C.READ_LP, 0, "read LP", fname)
+}
+
+// WriteLP writes problem data in CPLEX LP format to a file. If the
+// file name ends with ".gz" it is compressed. On failure an error (a
+// *FileError) is returned.
+func (p *Prob) WriteLP(fname string) error {
+	return p.rdwr(C.WRITE_LP, 0, "write LP", fname)
+}
+
+// ReadProb reads problem data in GLPK format from a file. The problem
+// is erased before reading. If the file name ends with ".gz" it is
+// decompressed. On failure an error (a *FileError containing GLPK
+// diagnostics) is returned.
+func (p *Prob) ReadProb(fname string) error {
+	return p.rdwr(C.READ_PROB, 0, "read GLPK", fname)
+}
+
+// WriteProb writes problem data in GLPK format to a file. If the file
+// name ends with ".gz" it is compressed. On failure an error (a
+// *FileError) is returned.
+func (p *Prob) WriteProb(fname string) error {
+	return p.rdwr(C.WRITE_PROB, 0, "write GLPK", fname)
+}
+
+// ReadCNFSAT reads a CNF-SAT problem in DIMACS CNF format from a
+// file. The problem is erased before reading and is then a MIP with a
+// binary column for each variable and a row for each clause (see
+// CheckCNFSAT). If the file name ends with ".gz" it is decompressed.
+// On failure an error (a *FileError containing GLPK diagnostics) is
+// returned. It returns ErrUnsupported if GLPK is older than 4.47.
+func (p *Prob) ReadCNFSAT(fname string) error {
+	return p.rdwr(C.READ_CNFSAT, 0, "read DIMACS CNF", fname)
+}
+
+// WriteCNFSAT writes a CNF-SAT problem (see CheckCNFSAT) in DIMACS CNF
+// format to a file. If the file name ends with ".gz" it is
+// compressed. On failure an error (a *FileError) is returned. It
+// returns ErrUnsupported if GLPK is older than 4.47.
+func (p *Prob) WriteCNFSAT(fname string) error {
+	return p.rdwr(C.WRITE_CNFSAT, 0, "write DIMACS CNF", fname)
+}
+
+// gunzip returns the data read from br decompressing it if it is
+// gzipped.
+func gunzip(br *bufio.Reader) (io.Reader, error) {
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		return gzip.NewReader(br)
+	}
+	return br, nil
+}
+
+// solDims returns the number of rows and columns of the problem a
+// solution in GLPK format in file fname is for. It reports false if
+// the file cannot be read or parsed (which is left to GLPK).
+func solDims(fname string) (m, n int, ok bool) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer f.Close()
+	r, err := gunzip(bufio.NewReader(f))
+	if err != nil {
+		return 0, 0, false
+	}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+		if fields[0] == "s" && len(fields) >= 2 {
+			// "s bas m n ..." since GLPK 4.57 ("m n" before)
+			fields = fields[2:]
+		}
+		if len(fields) < 2 {
+			return 0, 0, false
+		}
+		m, err1 := strconv.Atoi(fields[0])
+		n, err2 := strconv.Atoi(fields[1])
+		return m, n, err1 == nil && err2 == nil
+	}
+	return 0, 0, false
+}
+
+// readSol reads a solution with operation op after checking that it
+// matches the problem dimensions.
+func (p *Prob) readSol(op C.int, opname, fname string) error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if m, n, ok := solDims(fname); ok && (m != p.NumRows() || n != p.NumCols()) {
+		msg := fmt.Sprintf("solution is for %d rows and %d columns but problem has %d rows and %d columns", m, n, p.NumRows(), p.NumCols())
+		return &FileError{opname, fname, msg}
+	}
+	return p.rdwr(op, 0, opname, fname)
+}
+
+// PrintSol writes the basic solution in printable format to a file.
+// If the file name ends with ".gz" it is compressed. On failure an
+// error (a *FileError) is returned.
+func (p *Prob) PrintSol(fname string) error {
+	return p.rdwr(C.PRINT_SOL, 0, "print solution", fname)
+}
+
+// ReadSol reads the basic solution in GLPK format from a file (as
+// written by WriteSol). If the file name ends with ".gz" it is
+// decompressed. On failure (including the solution being for a
+// problem of other dimensions) an error (a *FileError) is returned.
+func (p *Prob) ReadSol(fname string) error {
+	return p.readSol(C.READ_SOL, "read solution", fname)
+}
+
+// WriteSol writes the basic solution in GLPK format to a file. If the
+// file name ends with ".gz" it is compressed. On failure an error (a
+// *FileError) is returned.
+func (p *Prob) WriteSol(fname string) error {
+	return p.rdwr(C.WRITE_SOL, 0, "write solution", fname)
+}
+
+// PrintIpt writes the interior-point solution in printable format to
+// a file. If the file name ends with ".gz" it is compressed. On
+// failure an error (a *FileError) is returned.
+func (p *Prob) PrintIpt(fname string) error {
+	return p.rdwr(C.PRINT_IPT, 0, "print interior-point solution", fname)
+}
+
+// ReadIpt reads the interior-point solution in GLPK format from a
+// file (as written by WriteIpt). If the file name ends with ".gz" it
+// is decompressed. On failure (including the solution being for a
+// problem of other dimensions) an error (a *FileError) is returned.
+func (p *Prob) ReadIpt(fname string) error {
+	return p.readSol(C.READ_IPT, "read interior-point solution", fname)
+}
+
+// WriteIpt writes the interior-point solution in GLPK format to a
+// file. If the file name ends with ".gz" it is compressed. On failure
+// an error (a *FileError) is returned.
+func (p *Prob) WriteIpt(fname string) error {
+	return p.rdwr(C.WRITE_IPT, 0, "write interior-point solution", fname)
+}
+
+// PrintMip writes the MIP solution in printable format to a file. If
+// the file name ends with ".gz" it is compressed. On failure an error
+// (a *FileError) is returned.
+func (p *Prob) PrintMip(fname string) error {
+	return p.rdwr(C.PRINT_MIP, 0, "print MIP solution", fname)
+}
+
+// ReadMip reads the MIP solution in GLPK format from a file (as
+// written by WriteMip). If the file name ends with ".gz" it is
+// decompressed. On failure (including the solution being for a
+// problem of other dimensions) an error (a *FileError) is returned.
+func (p *Prob) ReadMip(fname string) error {
+	return p.readSol(C.READ_MIP, "read MIP solution", fname)
+}
+
+// WriteMip writes the MIP solution in GLPK format to a file. If the
+// file name ends with ".gz" it is compressed. On failure an error (a
+// *FileError) is returned.
+func (p *Prob) WriteMip(fname string) error {
+	return p.rdwr(C.WRITE_MIP, 0, "write MIP solution", fname)
+}
+
+// readFrom copies data from r (decompressing it if it is gzipped) to
+// a temporary file and calls read with its name. The temporary file
+// is removed before returning.
+func readFrom(r io.Reader, read func(fname string) error) error {
+	f, err := ioutil.TempFile("", "glpk")
+	if err != nil {
+		return err
+	}
+	fname := f.Name()
+	defer os.Remove(fname)
+	if r, err = gunzip(bufio.NewReader(r)); err != nil {
+		f.Close()
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		return err
+	}
+	return hideFileName(read(fname), fname, "<reader>")
+}
+
+// writeTo calls write with a name of a temporary file and copies the
+// written data to w. The temporary file is removed before returning.
+func writeTo(w io.Writer, write func(fname string) error) error {
+	f, err := ioutil.TempFile("", "glpk")
+	if err != nil {
+		return err
+	}
+	fname := f.Name()
+	defer os.Remove(fname)
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := write(fname); err != nil {
+		return hideFileName(err, fname, "<writer>")
+	}
+	f, err = os.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// hideFileName replaces the temporary file name in err by name.
+func hideFileName(err error, fname, name string) error {
+	if e, ok := err.(*FileError); ok && e.Name == fname {
+		return &FileError{e.Op, name, strings.Replace(e.Msg, fname, name, -1)}
+	}
+	return err
+}
+
+// ReadMPSFrom reads problem data in fixed (glpk.MPS_DECK) or free
+// (glpk.MPS_FILE) MPS format from r. Gzip compressed data is detected
+// and decompressed. See also ReadMPS().
+func (p *Prob) ReadMPSFrom(r io.Reader, format MPSFormat) error {
+	return readFrom(r, func(fname string) error { return p.ReadMPS(format, fname) })
+}
+
+// WriteMPSTo writes problem data in fixed (glpk.MPS_DECK) or free
+// (glpk.MPS_FILE) MPS format to w. See also WriteMPS().
+func (p *Prob) WriteMPSTo(w io.Writer, format MPSFormat) error {
+	return writeTo(w, func(fname string) error { return p.WriteMPS(format, fname) })
+}
+
+// ReadLPFrom reads problem data in CPLEX LP format from r. Gzip
+// compressed data is detected and decompressed. See also ReadLP().
+func (p *Prob) ReadLPFrom(r io.Reader) error {
+	return readFrom(r, p.ReadLP)
+}
+
+// WriteLPTo writes problem data in CPLEX LP format to w. See also
+// WriteLP().
+func (p *Prob) WriteLPTo(w io.Writer) error {
+	return writeTo(w, p.WriteLP)
+}
+
+// ReadProbFrom reads problem data in GLPK format from r. Gzip
+// compressed data is detected and decompressed. See also ReadProb().
+func (p *Prob) ReadProbFrom(r io.Reader) error {
+	return readFrom(r, p.ReadProb)
+}
+
+// WriteProbTo writes problem data in GLPK format to w. See also
+// WriteProb().
+func (p *Prob) WriteProbTo(w io.Writer) error {
+	return writeTo(w, p.WriteProb)
+}
+
+// ReadCNFSATFrom reads a CNF-SAT problem in DIMACS CNF format from r.
+// Gzip compressed data is detected and decompressed. See also
+// ReadCNFSAT().
+func (p *Prob) ReadCNFSATFrom(r io.Reader) error {
+	return readFrom(r, p.ReadCNFSAT)
+}
+
+// WriteCNFSATTo writes a CNF-SAT problem in DIMACS CNF format to w.
+// See also WriteCNFSAT().
+func (p *Prob) WriteCNFSATTo(w io.Writer) error {
+	return writeTo(w, p.WriteCNFSAT)
+}
+
+// PrintSolTo writes the basic solution in printable format to w. See
+// also PrintSol().
+func (p *Prob) PrintSolTo(w io.Writer) error {
+	return writeTo(w, p.PrintSol)
+}
+
+// ReadSolFrom reads the basic solution in GLPK format from r. Gzip
+// compressed data is detected and decompressed. See also ReadSol().
+func (p *Prob) ReadSolFrom(r io.Reader) error {
+	return readFrom(r, p.ReadSol)
+}
+
+// WriteSolTo writes the basic solution in GLPK format to w. See also
+// WriteSol().
+func (p *Prob) WriteSolTo(w io.Writer) error {
+	return writeTo(w, p.WriteSol)
+}
+
+// PrintIptTo writes the interior-point solution in printable format
+// to w. See also PrintIpt().
+func (p *Prob) PrintIptTo(w io.Writer) error {
+	return writeTo(w, p.PrintIpt)
+}
+
+// ReadIptFrom reads the interior-point solution in GLPK format from
+// r. Gzip compressed data is detected and decompressed. See also
+// ReadIpt().
+func (p *Prob) ReadIptFrom(r io.Reader) error {
+	return readFrom(r, p.ReadIpt)
+}
+
+// WriteIptTo writes the interior-point solution in GLPK format to w.
+// See also WriteIpt().
+func (p *Prob) WriteIptTo(w io.Writer) error {
+	return writeTo(w, p.WriteIpt)
+}
+
+// PrintMipTo writes the MIP solution in printable format to w. See
+// also PrintMip().
+func (p *Prob) PrintMipTo(w io.Writer) error {
+	return writeTo(w, p.PrintMip)
+}
+
+// ReadMipFrom reads the MIP solution in GLPK format from r. Gzip
+// compressed data is detected and decompressed. See also ReadMip().
+func (p *Prob) ReadMipFrom(r io.Reader) error {
+	return readFrom(r, p.ReadMip)
+}
+
+// WriteMipTo writes the MIP solution in GLPK format to w. See also
+// WriteMip().
+func (p *Prob) WriteMipTo(w io.Writer) error {
+	return writeTo(w, p.WriteMip)
+}
@@ -0,0 +1,297 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// CheckSameProb checks that lp2 has the same rows, columns, bounds,
+// objective and constraint matrix as lp.
+func CheckSameProb(t *testing.T, lp, lp2 *Prob) {
+	if lp.NumRows() != lp2.NumRows() || lp.NumCols() != lp2.NumCols() {
+		t.Fatalf("Got %d x %d problem expected %d x %d", lp2.NumRows(), lp2.NumCols(), lp.NumRows(), lp.NumCols())
+	}
+	for i := 1; i <= lp.NumRows(); i++ {
+		if lp.RowName(i) != lp2.RowName(i) || lp.RowType(i) != lp2.RowType(i) ||
+			lp.RowLB(i) != lp2.RowLB(i) || lp.RowUB(i) != lp2.RowUB(i) {
+			t.Errorf("Row %d differs", i)
+		}
+		ind, val := lp.MatRow(i)
+		ind2, val2 := lp2.MatRow(i)
+		if !CmpIndicesData(ind, val, ind2, val2) {
+			t.Errorf("Indices and values (%v, %v) does not match (%v, %v)", ind2, val2, ind, val)
+		}
+	}
+	for j := 1; j <= lp.NumCols(); j++ {
+		if lp.ColName(j) != lp2.ColName(j) || lp.ColType(j) != lp2.ColType(j) ||
+			lp.ColLB(j) != lp2.ColLB(j) || lp.ColUB(j) != lp2.ColUB(j) ||
+			lp.ObjCoef(j) != lp2.ObjCoef(j) {
+			t.Errorf("Column %d differs", j)
+		}
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glpk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	lp := sampleProb()
+	tests := []struct {
+		name   string
+		write  func(string) error
+		read   func(*Prob, string) error
+		objDir bool // whether objective direction is preserved
+	}{
+		{"fixed.mps",
+			func(f string) error { return lp.WriteMPS(MPS_DECK, f) },
+			func(p *Prob, f string) error { return p.ReadMPS(MPS_DECK, f) },
+			false},
+		{"free.mps",
+			func(f string) error { return lp.WriteMPS(MPS_FILE, f) },
+			func(p *Prob, f string) error { return p.ReadMPS(MPS_FILE, f) },
+			false},
+		{"sample.lp", lp.WriteLP, (*Prob).ReadLP, true},
+		{"sample.glpk", lp.WriteProb, (*Prob).ReadProb, true},
+		{"sample.lp.gz", lp.WriteLP, (*Prob).ReadLP, true},
+	}
+	for _, test := range tests {
+		fname := filepath.Join(dir, test.name)
+		if err := test.write(fname); err != nil {
+			t.Errorf("%s: write error: %v", test.name, err)
+			continue
+		}
+		lp2 := New()
+		if err := test.read(lp2, fname); err != nil {
+			t.Errorf("%s: read error: %v", test.name, err)
+			lp2.Delete()
+			continue
+		}
+		CheckSameProb(t, lp, lp2)
+		if test.objDir && lp2.ObjDir() != MAX {
+			t.Errorf("%s: got objective direction %d expected %d (MAX)", test.name, lp2.ObjDir(), MAX)
+		}
+		lp2.Delete()
+	}
+	lp.Delete()
+}
+
+func TestReadError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glpk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fname := filepath.Join(dir, "bad.lp")
+	if err := ioutil.WriteFile(fname, []byte("maximize\n obj: x +\nsubject to\n c: x <= \nend\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lp := New()
+	err = lp.ReadLP(fname)
+	if e, ok := err.(*FileError); !ok || e.Name != fname || e.Msg == "" {
+		t.Errorf("Expected *FileError with diagnostics but got %#v", err)
+	}
+	err = lp.ReadProb(filepath.Join(dir, "nonexistent.glpk"))
+	if _, ok := err.(*FileError); !ok {
+		t.Errorf("Expected *FileError but got %#v", err)
+	}
+	if err := lp.WriteMPS(MPS_FILE, filepath.Join(dir, "nonexistent", "x.mps")); err == nil {
+		t.Errorf("Expected error writing to nonexistent directory")
+	}
+	lp.Delete()
+}
+
+func TestReadWriteStream(t *testing.T) {
+	lp := sampleProb()
+	var buf bytes.Buffer
+	tests := []struct {
+		name  string
+		write func() error
+		read  func(*Prob) error
+	}{
+		{"MPS",
+			func() error { return lp.WriteMPSTo(&buf, MPS_FILE) },
+			func(p *Prob) error { return p.ReadMPSFrom(&buf, MPS_FILE) }},
+		{"LP",
+			func() error { return lp.WriteLPTo(&buf) },
+			func(p *Prob) error { return p.ReadLPFrom(&buf) }},
+		{"GLPK",
+			func() error { return lp.WriteProbTo(&buf) },
+			func(p *Prob) error { return p.ReadProbFrom(&buf) }},
+	}
+	for _, test := range tests {
+		buf.Reset()
+		if err := test.write(); err != nil {
+			t.Errorf("%s: write error: %v", test.name, err)
+			continue
+		}
+		lp2 := New()
+		if err := test.read(lp2); err != nil {
+			t.Errorf("%s: read error: %v", test.name, err)
+		} else {
+			CheckSameProb(t, lp, lp2)
+		}
+		lp2.Delete()
+	}
+
+	// gzip compressed input is detected
+	buf.Reset()
+	if err := lp.WriteLPTo(&buf); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	var gzbuf bytes.Buffer
+	gz := gzip.NewWriter(&gzbuf)
+	gz.Write(buf.Bytes())
+	gz.Close()
+	lp2 := New()
+	if err := lp2.ReadLPFrom(&gzbuf); err != nil {
+		t.Errorf("gzip: read error: %v", err)
+	} else {
+		CheckSameProb(t, lp, lp2)
+	}
+	lp2.Delete()
+	lp.Delete()
+}
+
+func TestReadStreamError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glpk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tmpdir := os.Getenv("TMPDIR")
+	os.Setenv("TMPDIR", dir)
+	defer os.Setenv("TMPDIR", tmpdir)
+
+	lp := New()
+	err = lp.ReadLPFrom(bytes.NewBufferString("maximize\n obj: x +\nsubject to\n c: x <= \nend\n"))
+	if e, ok := err.(*FileError); !ok || e.Name != "<reader>" || e.Msg == "" {
+		t.Errorf("Expected *FileError for \"<reader>\" with diagnostics but got %#v", err)
+	} else if bytes.Contains([]byte(e.Msg), []byte(dir)) {
+		t.Errorf("Temporary file name leaked into error: %v", err)
+	}
+	if err := lp.ReadMPSFrom(bytes.NewBufferString("\x1f\x8bnot really gzip"), MPS_FILE); err == nil {
+		t.Errorf("Expected error for invalid gzip data")
+	}
+	lp.Delete()
+	if names, _ := filepath.Glob(filepath.Join(dir, "*")); len(names) != 0 {
+		t.Errorf("Temporary files left behind: %v", names)
+	}
+}
+
+func TestSolutionStream(t *testing.T) {
+	lp := sampleProb()
+	defer lp.Delete()
+	lp.SetTermWriter(ioutil.Discard)
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	if err := lp.Interior(nil); err != nil {
+		t.Fatalf("Interior error: %v", err)
+	}
+	mip := sampleProb()
+	defer mip.Delete()
+	mip.SetTermWriter(ioutil.Discard)
+	for j := 1; j <= mip.NumCols(); j++ {
+		mip.SetColKind(j, IV)
+	}
+	iocp := NewIocp()
+	iocp.SetPresolve(true)
+	if err := mip.Intopt(iocp); err != nil {
+		t.Fatalf("Intopt error: %v", err)
+	}
+
+	same := func(a, b float64) bool {
+		return math.Abs(a-b) <= 1e-9*(1+math.Abs(a))
+	}
+	var buf bytes.Buffer
+	tests := []struct {
+		name  string
+		p     *Prob
+		print func(p *Prob) error
+		write func(p *Prob) error
+		read  func(p *Prob) error
+		check func(p, p2 *Prob) bool
+	}{
+		{"basic", lp,
+			func(p *Prob) error { return p.PrintSolTo(&buf) },
+			func(p *Prob) error { return p.WriteSolTo(&buf) },
+			func(p *Prob) error { return p.ReadSolFrom(&buf) },
+			func(p, p2 *Prob) bool {
+				return p2.Status() == p.Status() && same(p2.ObjVal(), p.ObjVal()) &&
+					same(p2.ColPrim(2), p.ColPrim(2)) && same(p2.RowDual(1), p.RowDual(1))
+			}},
+		{"interior-point", lp,
+			func(p *Prob) error { return p.PrintIptTo(&buf) },
+			func(p *Prob) error { return p.WriteIptTo(&buf) },
+			func(p *Prob) error { return p.ReadIptFrom(&buf) },
+			func(p, p2 *Prob) bool {
+				return p2.IptStatus() == p.IptStatus() && same(p2.IptObjVal(), p.IptObjVal()) &&
+					same(p2.IptColPrim(2), p.IptColPrim(2))
+			}},
+		{"MIP", mip,
+			func(p *Prob) error { return p.PrintMipTo(&buf) },
+			func(p *Prob) error { return p.WriteMipTo(&buf) },
+			func(p *Prob) error { return p.ReadMipFrom(&buf) },
+			func(p, p2 *Prob) bool {
+				return p2.MipStatus() == p.MipStatus() && same(p2.MipObjVal(), p.MipObjVal()) &&
+					same(p2.MipColVal(2), p.MipColVal(2))
+			}},
+	}
+	for _, test := range tests {
+		buf.Reset()
+		if err := test.print(test.p); err != nil {
+			t.Errorf("%s: print error: %v", test.name, err)
+		} else if !strings.Contains(buf.String(), "Objective:") {
+			t.Errorf("%s: unexpected printed solution %q", test.name, buf.String())
+		}
+
+		buf.Reset()
+		if err := test.write(test.p); err != nil {
+			t.Errorf("%s: write error: %v", test.name, err)
+			continue
+		}
+		data := buf.String()
+		p2 := test.p.Copy(false)
+		p2.SetTermWriter(ioutil.Discard)
+		if err := test.read(p2); err != nil {
+			t.Errorf("%s: read error: %v", test.name, err)
+		} else if !test.check(test.p, p2) {
+			t.Errorf("%s: solution differs after writing and reading", test.name)
+		}
+
+		// solution for a problem of other dimensions is rejected
+		p2.AddCols(1)
+		buf.Reset()
+		buf.WriteString(data)
+		err := test.read(p2)
+		if e, ok := err.(*FileError); !ok || e.Name != "<reader>" || !strings.Contains(e.Msg, "columns") {
+			t.Errorf("%s: expected *FileError for mismatched dimensions got %v", test.name, err)
+		}
+		p2.Delete()
+	}
+}
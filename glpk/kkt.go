@@ -0,0 +1,80 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "fmt"
+
+// #include <glpk.h>
+// #include "guard.h"
+//
+// GUARDED_VOID(go_glp_check_kkt, (gcall *c, glp_prob *P, int sol, int cond, double *ae_max, int *ae_ind, double *re_max, int *re_ind), glp_check_kkt(P, sol, cond, ae_max, ae_ind, re_max, re_ind))
+import "C"
+
+// Karush-Kuhn-Tucker optimality condition
+type KKTCond int
+
+const (
+	KKT_PE = KKTCond(C.GLP_KKT_PE) // primal equality constraints
+	KKT_PB = KKTCond(C.GLP_KKT_PB) // primal bound constraints
+	KKT_DE = KKTCond(C.GLP_KKT_DE) // dual equality constraints
+	KKT_DB = KKTCond(C.GLP_KKT_DB) // dual bound constraints
+)
+
+// KKT is the result of checking a Karush-Kuhn-Tucker optimality
+// condition (see Prob.CheckKKT). Variables are referred to by their
+// ordinal numbers as in sensitivity analysis: k = 1..m for rows and
+// k = m+1..m+n for columns.
+type KKT struct {
+	AbsErr float64 // maximal absolute error
+	AbsVar int     // ordinal number of the row or column with AbsErr (0 if there is no error)
+	RelErr float64 // maximal relative error
+	RelVar int     // ordinal number of the row or column with RelErr (0 if there is no error)
+}
+
+// CheckKKT checks how accurately the solution of type sol (glpk.SOL,
+// glpk.IPT or glpk.MIP) satisfies the optimality condition cond:
+//
+//     KKT_PE  primal equality constraints (errors of rows 1..m)
+//     KKT_PB  primal bound constraints (of rows and columns)
+//     KKT_DE  dual equality constraints (of columns m+1..m+n)
+//     KKT_DB  dual bound constraints (of rows and columns)
+//
+// Only primal conditions may be checked for a MIP solution. An error
+// is returned for an invalid solution type or condition.
+func (p *Prob) CheckKKT(sol SolType, cond KKTCond) (*KKT, error) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if sol != SOL && sol != IPT && sol != MIP {
+		return nil, fmt.Errorf("glpk: CheckKKT: invalid solution type %d", sol)
+	}
+	if cond < KKT_PE || cond > KKT_DB {
+		return nil, fmt.Errorf("glpk: CheckKKT: invalid condition %d", cond)
+	}
+	if sol == MIP && (cond == KKT_DE || cond == KKT_DB) {
+		return nil, fmt.Errorf("glpk: CheckKKT: dual conditions are not defined for MIP solution")
+	}
+	var aeMax, reMax C.double
+	var aeInd, reInd C.int
+	c := newCall(p.p.w)
+	C.go_glp_check_kkt(&c.c, p.p.p, C.int(sol), C.int(cond), &aeMax, &aeInd, &reMax, &reInd)
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return &KKT{float64(aeMax), int(aeInd), float64(reMax), int(reInd)}, nil
+}
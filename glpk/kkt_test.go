@@ -0,0 +1,64 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestCheckKKT(t *testing.T) {
+	lp := sampleProb()
+	defer lp.Delete()
+	lp.SetTermWriter(ioutil.Discard)
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	for _, cond := range []KKTCond{KKT_PE, KKT_PB, KKT_DE, KKT_DB} {
+		kkt, err := lp.CheckKKT(SOL, cond)
+		if err != nil {
+			t.Fatalf("CheckKKT(SOL, %d) error: %v", cond, err)
+		}
+		if kkt.AbsErr > 1e-9 || kkt.RelErr > 1e-9 {
+			t.Errorf("CheckKKT(SOL, %d) = %+v expected no errors", cond, *kkt)
+		}
+	}
+
+	// row 1 is active at its upper bound 100 which is violated by 10
+	// after changing the bound (without solving the problem again)
+	lp.SetRowBnds(1, UP, 0, 90)
+	kkt, err := lp.CheckKKT(SOL, KKT_PB)
+	if err != nil {
+		t.Fatalf("CheckKKT error: %v", err)
+	}
+	if kkt.AbsVar != 1 || kkt.RelVar != 1 {
+		t.Errorf("Got maximal errors at %d and %d expected 1", kkt.AbsVar, kkt.RelVar)
+	}
+	CheckCloseTol(t, kkt.AbsErr, 10, 1e-6)
+	CheckCloseTol(t, kkt.RelErr, 10.0/91, 1e-6)
+
+	if _, err := lp.CheckKKT(MIP, KKT_DE); err == nil {
+		t.Errorf("CheckKKT of dual condition for MIP solution succeeded")
+	}
+	if _, err := lp.CheckKKT(SolType(0), KKT_PE); err == nil {
+		t.Errorf("CheckKKT with invalid solution type succeeded")
+	}
+	if _, err := lp.CheckKKT(SOL, KKTCond(0)); err == nil {
+		t.Errorf("CheckKKT with invalid condition succeeded")
+	}
+}
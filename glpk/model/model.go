@@ -0,0 +1,431 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+// Package model provides a modeling layer on top of glpk.Prob for
+// building linear (and mixed integer) programs with variables, linear
+// expressions and named constraints instead of row and column
+// indices. Usage example:
+//
+//     m := model.New("sample")
+//     x := m.AddVar("x", 0, math.Inf(1))
+//     y := m.AddIntVar("y", 0, 10)
+//     m.Le("c1", model.Sum(x.Mul(2), y.Mul(3)), 12)
+//     m.Maximize(model.Sum(x.Expr(), y.Mul(2)))
+//     lp, err := m.Build()
+//     if err != nil { ... }
+//     defer lp.Delete()
+//     lp.Simplex(nil)
+//     lp.Intopt(nil)
+//     fmt.Println(x.Value(), y.Value())
+//
+// Constraints are stored in the coordinate (triplet) format as they
+// are added, so Build() loads the whole constraint matrix with a
+// single call to glpk.Prob.LoadMatrix() and the cost of building a
+// model is linear in the number of its nonzeros.
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/lukpank/go-glpk/glpk"
+)
+
+// Var is a handle of a variable (a column) of a model.
+type Var struct {
+	m *Model
+	j int // column index
+}
+
+// Term is a variable multiplied by a coefficient.
+type Term struct {
+	Var  Var
+	Coef float64
+}
+
+// LinExpr is a linear expression: the sum of its terms and the
+// constant. Methods Add, Scale and functions Sum return new
+// expressions (not sharing memory with their arguments) while
+// AddTerm and AddExpr modify the expression in place, which should be
+// used to accumulate long expressions in loops.
+type LinExpr struct {
+	Terms []Term
+	Const float64
+}
+
+// Constr is a handle of a constraint (a row) of a model.
+type Constr struct {
+	m *Model
+	i int // row index
+}
+
+type varData struct {
+	name   string
+	lo, hi float64
+	kind   glpk.VarType
+}
+
+type constrData struct {
+	name   string
+	lo, hi float64
+}
+
+// Model is a linear or mixed integer programming model.
+type Model struct {
+	name   string
+	vars   []varData // vars[j-1] is j-th column
+	cons   []constrData
+	ia, ja []int32 // constraint matrix in coordinate format (with unused element 0)
+	ar     []float64
+	pos    []int // used to merge duplicate terms of a constraint
+	dir    glpk.ObjDir
+	obj    LinExpr
+	err    error // first error detected while building
+	prob   *glpk.Prob
+	isMIP  bool
+}
+
+// New creates a new empty model with the given name.
+func New(name string) *Model {
+	return &Model{name: name, dir: glpk.MIN, ia: []int32{0}, ja: []int32{0}, ar: []float64{0}, pos: []int{0}}
+}
+
+// addVar adds a variable with bounds lo and hi (which may be infinite).
+func (m *Model) addVar(name string, lo, hi float64, kind glpk.VarType) Var {
+	if math.IsNaN(lo) || math.IsNaN(hi) || lo > hi {
+		m.setErr(fmt.Errorf("model: invalid bounds [%g, %g] of variable %q", lo, hi, name))
+	}
+	m.vars = append(m.vars, varData{name, lo, hi, kind})
+	m.pos = append(m.pos, 0)
+	if kind != glpk.CV {
+		m.isMIP = true
+	}
+	return Var{m, len(m.vars)}
+}
+
+// AddVar adds a continuous variable with bounds lo and hi (use
+// math.Inf(-1) and math.Inf(1) for no bound).
+func (m *Model) AddVar(name string, lo, hi float64) Var {
+	return m.addVar(name, lo, hi, glpk.CV)
+}
+
+// AddIntVar adds an integer variable with bounds lo and hi (see
+// AddVar).
+func (m *Model) AddIntVar(name string, lo, hi float64) Var {
+	return m.addVar(name, lo, hi, glpk.IV)
+}
+
+// AddBinVar adds a binary variable.
+func (m *Model) AddBinVar(name string) Var {
+	return m.addVar(name, 0, 1, glpk.BV)
+}
+
+// NumVars returns the number of variables.
+func (m *Model) NumVars() int {
+	return len(m.vars)
+}
+
+// NumConstrs returns the number of constraints.
+func (m *Model) NumConstrs() int {
+	return len(m.cons)
+}
+
+// NumNz returns the number of (distinct) nonzero coefficients of the
+// constraints.
+func (m *Model) NumNz() int {
+	return len(m.ia) - 1
+}
+
+func (m *Model) setErr(err error) {
+	if m.err == nil {
+		m.err = err
+	}
+}
+
+// Expr returns the expression 1 * v.
+func (v Var) Expr() LinExpr {
+	return LinExpr{Terms: []Term{{v, 1}}}
+}
+
+// Mul returns the expression c * v.
+func (v Var) Mul(c float64) LinExpr {
+	return LinExpr{Terms: []Term{{v, c}}}
+}
+
+// Name returns the name of the variable.
+func (v Var) Name() string {
+	return v.m.vars[v.j-1].name
+}
+
+// Index returns the (1-based) index of the column of the variable in
+// the problem created by Model.Build().
+func (v Var) Index() int {
+	return v.j
+}
+
+// Value returns the value of the variable in the solution of the
+// problem created by Model.Build(): the MIP solution if the model has
+// integer variables or the basic solution otherwise.
+func (v Var) Value() float64 {
+	p := v.m.built()
+	if v.m.isMIP {
+		return p.MipColVal(v.j)
+	}
+	return p.ColPrim(v.j)
+}
+
+// Dual returns the reduced cost of the variable in the basic solution
+// of the problem created by Model.Build().
+func (v Var) Dual() float64 {
+	return v.m.built().ColDual(v.j)
+}
+
+// Const returns the constant expression c.
+func Const(c float64) LinExpr {
+	return LinExpr{Const: c}
+}
+
+// Sum returns the sum of the expressions.
+func Sum(es ...LinExpr) LinExpr {
+	n := 0
+	for _, e := range es {
+		n += len(e.Terms)
+	}
+	s := LinExpr{Terms: make([]Term, 0, n)}
+	for _, e := range es {
+		s.AddExpr(e)
+	}
+	return s
+}
+
+// SumVars returns the sum of the variables (with unit coefficients).
+func SumVars(vs ...Var) LinExpr {
+	s := LinExpr{Terms: make([]Term, len(vs))}
+	for k, v := range vs {
+		s.Terms[k] = Term{v, 1}
+	}
+	return s
+}
+
+// Add returns the sum e + f.
+func (e LinExpr) Add(f LinExpr) LinExpr {
+	return Sum(e, f)
+}
+
+// Scale returns the expression c * e.
+func (e LinExpr) Scale(c float64) LinExpr {
+	s := LinExpr{Terms: make([]Term, len(e.Terms)), Const: c * e.Const}
+	for k, t := range e.Terms {
+		s.Terms[k] = Term{t.Var, c * t.Coef}
+	}
+	return s
+}
+
+// AddTerm adds c * v to e (in place).
+func (e *LinExpr) AddTerm(v Var, c float64) {
+	e.Terms = append(e.Terms, Term{v, c})
+}
+
+// AddExpr adds f to e (in place).
+func (e *LinExpr) AddExpr(f LinExpr) {
+	e.Terms = append(e.Terms, f.Terms...)
+	e.Const += f.Const
+}
+
+// addConstr adds constraint lo <= e <= hi (with the constant of e
+// moved to the bounds).
+func (m *Model) addConstr(name string, lo float64, e LinExpr, hi float64) Constr {
+	i := len(m.cons) + 1
+	m.cons = append(m.cons, constrData{name, lo - e.Const, hi - e.Const})
+	if math.IsNaN(lo) || math.IsNaN(hi) || lo > hi {
+		m.setErr(fmt.Errorf("model: invalid bounds [%g, %g] of constraint %q", lo, hi, name))
+	}
+	start := len(m.ia)
+	for _, t := range e.Terms {
+		if t.Var.m != m {
+			m.setErr(fmt.Errorf("model: constraint %q uses a variable of another model", name))
+			continue
+		}
+		if math.IsNaN(t.Coef) || math.IsInf(t.Coef, 0) {
+			m.setErr(fmt.Errorf("model: invalid coefficient %g of variable %q in constraint %q", t.Coef, t.Var.Name(), name))
+		}
+		if k := m.pos[t.Var.j]; k != 0 {
+			m.ar[k] += t.Coef
+			continue
+		}
+		m.pos[t.Var.j] = len(m.ia)
+		m.ia = append(m.ia, int32(i))
+		m.ja = append(m.ja, int32(t.Var.j))
+		m.ar = append(m.ar, t.Coef)
+	}
+	for k := start; k < len(m.ia); k++ {
+		m.pos[m.ja[k]] = 0
+	}
+	return Constr{m, i}
+}
+
+// Le adds constraint e <= rhs.
+func (m *Model) Le(name string, e LinExpr, rhs float64) Constr {
+	return m.addConstr(name, math.Inf(-1), e, rhs)
+}
+
+// Ge adds constraint e >= rhs.
+func (m *Model) Ge(name string, e LinExpr, rhs float64) Constr {
+	return m.addConstr(name, rhs, e, math.Inf(1))
+}
+
+// Eq adds constraint e = rhs.
+func (m *Model) Eq(name string, e LinExpr, rhs float64) Constr {
+	return m.addConstr(name, rhs, e, rhs)
+}
+
+// Range adds constraint lo <= e <= hi.
+func (m *Model) Range(name string, lo float64, e LinExpr, hi float64) Constr {
+	return m.addConstr(name, lo, e, hi)
+}
+
+// Name returns the name of the constraint.
+func (c Constr) Name() string {
+	return c.m.cons[c.i-1].name
+}
+
+// Index returns the (1-based) index of the row of the constraint in
+// the problem created by Model.Build().
+func (c Constr) Index() int {
+	return c.i
+}
+
+// Value returns the value of the constraint expression (without its
+// constant) in the solution of the problem created by Model.Build()
+// (see Var.Value).
+func (c Constr) Value() float64 {
+	p := c.m.built()
+	if c.m.isMIP {
+		return p.MipRowVal(c.i)
+	}
+	return p.RowPrim(c.i)
+}
+
+// Dual returns the dual value (shadow price) of the constraint in the
+// basic solution of the problem created by Model.Build().
+func (c Constr) Dual() float64 {
+	return c.m.built().RowDual(c.i)
+}
+
+// Minimize sets the objective to minimize e.
+func (m *Model) Minimize(e LinExpr) {
+	m.dir, m.obj = glpk.MIN, e
+}
+
+// Maximize sets the objective to maximize e.
+func (m *Model) Maximize(e LinExpr) {
+	m.dir, m.obj = glpk.MAX, e
+}
+
+// ObjVal returns the objective value of the solution of the problem
+// created by Model.Build() (see Var.Value).
+func (m *Model) ObjVal() float64 {
+	p := m.built()
+	if m.isMIP {
+		return p.MipObjVal()
+	}
+	return p.ObjVal()
+}
+
+// ErrNotBuilt is returned (as a panic value) when solution values are
+// requested before Model.Build() is called.
+var ErrNotBuilt = errors.New("model: Build not called")
+
+func (m *Model) built() *glpk.Prob {
+	if m.prob == nil {
+		panic(ErrNotBuilt)
+	}
+	return m.prob
+}
+
+// bnds returns GLPK bounds type and bounds for [lo, hi].
+func bnds(lo, hi float64) (glpk.BndsType, float64, float64) {
+	loInf, hiInf := math.IsInf(lo, -1), math.IsInf(hi, 1)
+	switch {
+	case loInf && hiInf:
+		return glpk.FR, 0, 0
+	case hiInf:
+		return glpk.LO, lo, 0
+	case loInf:
+		return glpk.UP, 0, hi
+	case lo == hi:
+		return glpk.FX, lo, hi
+	}
+	return glpk.DB, lo, hi
+}
+
+// Build creates a new problem from the model. Solution values may
+// then be read using Var and Constr handles after solving the problem.
+// The problem is owned by the caller (who should eventually Delete
+// it). Build returns the first error detected while the model was
+// built (such as invalid bounds or coefficients).
+func (m *Model) Build() (*glpk.Prob, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	p := glpk.New()
+	p.SetProbName(m.name)
+	p.SetObjDir(m.dir)
+	if len(m.cons) > 0 {
+		p.AddRows(len(m.cons))
+	}
+	for k, c := range m.cons {
+		i := k + 1
+		if c.name != "" {
+			p.SetRowName(i, c.name)
+		}
+		type_, lo, hi := bnds(c.lo, c.hi)
+		p.SetRowBnds(i, type_, lo, hi)
+	}
+	if len(m.vars) > 0 {
+		p.AddCols(len(m.vars))
+	}
+	for k, v := range m.vars {
+		j := k + 1
+		if v.name != "" {
+			p.SetColName(j, v.name)
+		}
+		if v.kind != glpk.CV {
+			p.SetColKind(j, v.kind)
+		}
+		type_, lo, hi := bnds(v.lo, v.hi)
+		p.SetColBnds(j, type_, lo, hi)
+	}
+	obj := make([]float64, len(m.vars)+1)
+	for _, t := range m.obj.Terms {
+		if t.Var.m != m {
+			p.Delete()
+			return nil, errors.New("model: objective uses a variable of another model")
+		}
+		obj[t.Var.j] += t.Coef
+	}
+	for j := 1; j < len(obj); j++ {
+		if obj[j] != 0 {
+			p.SetObjCoef(j, obj[j])
+		}
+	}
+	p.SetObjCoef(0, m.obj.Const)
+	p.LoadMatrix(m.ia, m.ja, m.ar)
+	m.prob = p
+	return p, nil
+}
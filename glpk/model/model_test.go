@@ -0,0 +1,156 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import (
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/lukpank/go-glpk/glpk"
+)
+
+func checkClose(t *testing.T, what string, v1, v2 float64) {
+	if math.Abs(v1-v2) > 1e-9*math.Max(1, math.Abs(v2)) {
+		t.Errorf("%s: %g != %g", what, v1, v2)
+	}
+}
+
+func TestSample(t *testing.T) {
+	m := New("sample")
+	x := []Var{
+		m.AddVar("x0", 0, math.Inf(1)),
+		m.AddVar("x1", 0, math.Inf(1)),
+		m.AddVar("x2", 0, math.Inf(1)),
+	}
+	p := m.Le("p", SumVars(x...), 100)
+	q := m.Le("q", Sum(x[0].Mul(10), x[1].Mul(4), x[2].Mul(5)), 600)
+	// constant and duplicate terms: 2 x0 + x1 + x1 + 6 x2 + 10 <= 310
+	e := Sum(x[0].Mul(2), x[1].Expr(), Const(10))
+	e.AddTerm(x[1], 1)
+	e.AddTerm(x[2], 6)
+	r := m.Le("r", e, 310)
+	m.Maximize(Sum(x[0].Mul(10), x[1].Mul(6), x[2].Mul(4)))
+	if m.NumNz() != 9 {
+		t.Errorf("expected 9 nonzeros but got %d", m.NumNz())
+	}
+
+	lp, err := m.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lp.Delete()
+	lp.SetTermWriter(ioutil.Discard)
+	if lp.RowName(r.Index()) != "r" || lp.RowUB(r.Index()) != 300 {
+		t.Errorf("constant not moved to the bound of %s", r.Name())
+	}
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatal(err)
+	}
+	checkClose(t, "objective", m.ObjVal(), 733.33333333333333)
+	checkClose(t, "x0", x[0].Value(), 33.33333333333333)
+	checkClose(t, "x1", x[1].Value(), 66.66666666666667)
+	checkClose(t, "x2", x[2].Value(), 0)
+	checkClose(t, "p", p.Value(), 100)
+	checkClose(t, "p dual", p.Dual(), 10.0/3)
+	checkClose(t, "q dual", q.Dual(), 2.0/3)
+	checkClose(t, "x2 dual", x[2].Dual(), -8.0/3)
+}
+
+func TestMIP(t *testing.T) {
+	m := New("knapsack")
+	w := []float64{5, 3, 2, 4}
+	v := []float64{10, 9, 7, 12}
+	var weight, value LinExpr
+	x := make([]Var, len(w))
+	for k := range w {
+		x[k] = m.AddBinVar("")
+		weight.AddTerm(x[k], w[k])
+		value.AddTerm(x[k], v[k])
+	}
+	m.Le("weight", weight, 9)
+	m.Maximize(value.Add(Const(1)))
+	lp, err := m.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lp.Delete()
+	lp.SetTermWriter(ioutil.Discard)
+	iocp := glpk.NewIocp()
+	iocp.SetPresolve(true)
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatal(err)
+	}
+	checkClose(t, "objective", m.ObjVal(), 29)
+	for k, want := range []float64{0, 1, 1, 1} {
+		checkClose(t, "x", x[k].Value(), want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	m := New("")
+	x := m.AddVar("x", 1, 0)
+	if _, err := m.Build(); err == nil {
+		t.Errorf("expected error for invalid bounds")
+	}
+	m = New("")
+	m.Ge("c", x.Expr(), 1)
+	if _, err := m.Build(); err == nil {
+		t.Errorf("expected error for a variable of another model")
+	}
+	m = New("")
+	y := m.AddVar("y", 0, 1)
+	m.Eq("c", y.Mul(math.NaN()), 1)
+	if _, err := m.Build(); err == nil {
+		t.Errorf("expected error for NaN coefficient")
+	}
+	defer func() {
+		if recover() != ErrNotBuilt {
+			t.Errorf("expected ErrNotBuilt panic")
+		}
+	}()
+	y.Value()
+}
+
+func TestLarge(t *testing.T) {
+	const n = 1000
+	m := New("large")
+	x := make([]Var, n)
+	for j := range x {
+		x[j] = m.AddVar("", 0, 1)
+	}
+	for i := 0; i < n; i++ {
+		var e LinExpr
+		for j := range x {
+			e.AddTerm(x[j], float64(1+(i+j)%7))
+		}
+		m.Range("", 0, e, float64(n))
+	}
+	m.Maximize(SumVars(x...))
+	if m.NumNz() != n*n {
+		t.Fatalf("expected %d nonzeros but got %d", n*n, m.NumNz())
+	}
+	lp, err := m.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lp.Delete()
+	if lp.NumNz() != n*n || lp.RowType(1) != glpk.DB {
+		t.Errorf("unexpected problem: %d nonzeros, row type %d", lp.NumNz(), lp.RowType(1))
+	}
+}
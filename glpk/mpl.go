@@ -0,0 +1,321 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"errors"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// #include <glpk.h>
+// #include <stdlib.h>
+// #include "guard.h"
+//
+// // alloc_wksp creates a translator and stores the id of the GLPK
+// // environment it belongs to in env.
+// static glp_tran *alloc_wksp(uint64_t *env) {
+//	*env = guard_env();
+//	return glp_mpl_alloc_wksp();
+// }
+//
+// enum { MPL_READ_MODEL, MPL_READ_DATA, MPL_GENERATE, MPL_POSTSOLVE };
+//
+// // mpl_op performs operation op of the MathProg translator.
+// static int mpl_op(glp_tran *tran, int op, const char *fname, int arg, glp_prob *P) {
+//	int ret = 1;
+//	switch (op) {
+//	case MPL_READ_MODEL:
+//		ret = glp_mpl_read_model(tran, fname, arg);
+//		break;
+//	case MPL_READ_DATA:
+//		ret = glp_mpl_read_data(tran, fname);
+//		break;
+//	case MPL_GENERATE:
+//		ret = glp_mpl_generate(tran, fname);
+//		break;
+//	case MPL_POSTSOLVE:
+//		ret = glp_mpl_postsolve(tran, P, arg);
+//		break;
+//	}
+//	return ret;
+// }
+//
+// GUARDED(int, mpl_call, (gcall *c, glp_tran *tran, int op, const char *fname, int arg, glp_prob *P), mpl_op(tran, op, fname, arg, P))
+// GUARDED_VOID(go_glp_mpl_build_prob, (gcall *c, glp_tran *tran, glp_prob *P), glp_mpl_build_prob(tran, P))
+import "C"
+
+// MPLError describes an error in a MathProg model or data section
+// (or an error detected during model generation or postsolving).
+type MPLError struct {
+	File    string // name of the model or data file
+	Line    int    // line number (0 if unknown)
+	Msg     string // error message
+	Context string // context of the error as printed by GLPK (may be empty)
+}
+
+func (e *MPLError) Error() string {
+	if e.File == "" {
+		return "glpk: " + e.Msg
+	}
+	return "glpk: " + e.File + ":" + strconv.Itoa(e.Line) + ": " + e.Msg
+}
+
+var mplErrorRe = regexp.MustCompile(`^(.*):([0-9]+): (.*)$`)
+
+// parseMPLError extracts the error location and message from the
+// terminal output of the MathProg translator.
+func parseMPLError(out string) *MPLError {
+	e := &MPLError{}
+	for _, line := range strings.Split(out, "\n") {
+		if e.Msg == "" {
+			if m := mplErrorRe.FindStringSubmatch(line); m != nil {
+				e.File = m[1]
+				e.Line, _ = strconv.Atoi(m[2])
+				e.Msg = m[3]
+			}
+		} else if strings.HasPrefix(line, "Context: ") {
+			e.Context = strings.TrimPrefix(line, "Context: ")
+			break
+		}
+	}
+	if e.Msg == "" {
+		e.Msg = strings.Replace(strings.TrimSpace(out), "\n", "; ", -1)
+		if e.Msg == "" {
+			e.Msg = "MathProg translator error"
+		}
+	}
+	return e
+}
+
+// ErrTranState is returned by Tran methods called out of order (or
+// after a previous error).
+var ErrTranState = errors.New("glpk: invalid call sequence of MathProg translator")
+
+// phases of the MathProg translator
+const (
+	tranInit = iota
+	tranModel
+	tranData
+	tranGenerated
+	tranPostsolved
+	tranError
+)
+
+type tran struct {
+	t     *C.glp_tran
+	env   uint64 // id of the GLPK environment t belongs to
+	phase int
+	m, n  int        // problem size at the time of BuildProb()
+	w     termWriter // terminal output writer (see SetTermWriter)
+}
+
+// Tran represents the MathProg (GMPL) translator workspace. Use
+// glpk.NewTran() to create a new translator. Typical usage is
+//
+//     tran := glpk.NewTran()
+//     defer tran.Delete()
+//     if err := tran.ReadModel("model.mod", false); err != nil { ... }
+//     if err := tran.ReadData("model.dat"); err != nil { ... }
+//     if err := tran.Generate(""); err != nil { ... }
+//     lp := glpk.New()
+//     tran.BuildProb(lp)
+//     lp.Simplex(nil)
+//     if err := tran.Postsolve(lp, glpk.SOL); err != nil { ... }
+//
+type Tran struct {
+	t *tran
+}
+
+// invalid reports whether the translator was deleted (or freed along
+// with its GLPK environment after a fatal error).
+func (t *tran) invalid() bool {
+	return t.t == nil || envDead(t.env)
+}
+
+// free frees the translator workspace unless it was already freed.
+func (t *tran) free() {
+	if t.t != nil && !envDead(t.env) {
+		C.guard_free(C.GUARD_TRAN, unsafe.Pointer(t.t), C.uint64_t(t.env))
+	}
+	t.t = nil
+	t.w.release()
+	t.w = 0
+}
+
+func finalizeTran(t *tran) {
+	t.free()
+}
+
+// NewTran creates a new MathProg translator workspace.
+func NewTran() *Tran {
+	var env C.uint64_t
+	t := &tran{t: C.alloc_wksp(&env)}
+	t.env = uint64(env)
+	runtime.SetFinalizer(t, finalizeTran)
+	return &Tran{t}
+}
+
+// Delete frees the translator workspace. Calling Delete on a deleted
+// translator will have no effect. But calling any other method on a
+// deleted translator will panic.
+func (t *Tran) Delete() {
+	t.t.free()
+}
+
+// InitRand initializes the pseudo-random number generator used by
+// the translator with the given seed (default seed is 1). It should
+// be called before ReadModel().
+func (t *Tran) InitRand(seed int) {
+	if t.t.invalid() {
+		panic("Tran method called on a deleted translator")
+	}
+	C.glp_mpl_init_rand(t.t.t, C.int(seed))
+}
+
+func (t *Tran) call(op C.int, fname string, arg int, p *Prob) error {
+	var s *C.char
+	if fname != "" {
+		s = C.CString(fname)
+		defer C.free(unsafe.Pointer(s))
+	}
+	var pp *C.glp_prob
+	if p != nil {
+		pp = p.p.p
+	}
+	c := newCall(t.t.w).collect()
+	ret := C.mpl_call(&c.c, t.t.t, op, s, C.int(arg), pp)
+	if err := c.err(); err != nil {
+		t.t.phase = tranError
+		return err
+	}
+	if ret != 0 {
+		t.t.phase = tranError
+		return parseMPLError(c.out)
+	}
+	return nil
+}
+
+// ReadModel reads the model section and, unless skipData is true, the
+// data section (if present) from the given file. On error (which is
+// an instance of *MPLError) the translator can only be deleted.
+func (t *Tran) ReadModel(fname string, skipData bool) error {
+	if t.t.invalid() {
+		panic("Tran method called on a deleted translator")
+	}
+	if t.t.phase != tranInit {
+		return ErrTranState
+	}
+	skip := 0
+	if skipData {
+		skip = 1
+	}
+	if err := t.call(C.MPL_READ_MODEL, fname, skip, nil); err != nil {
+		return err
+	}
+	t.t.phase = tranModel
+	return nil
+}
+
+// ReadData reads a data section from the given file. It may be called
+// several times (after ReadModel()) to read data from many files. On
+// error (which is an instance of *MPLError) the translator can only
+// be deleted.
+func (t *Tran) ReadData(fname string) error {
+	if t.t.invalid() {
+		panic("Tran method called on a deleted translator")
+	}
+	if t.t.phase != tranModel && t.t.phase != tranData {
+		return ErrTranState
+	}
+	if err := t.call(C.MPL_READ_DATA, fname, 0, nil); err != nil {
+		return err
+	}
+	t.t.phase = tranData
+	return nil
+}
+
+// Generate generates the model (executing statements up to the solve
+// statement). Output of display and printf statements is written to
+// the file fname or, if fname is empty, to the terminal. On error
+// (which is an instance of *MPLError) the translator can only be
+// deleted.
+func (t *Tran) Generate(fname string) error {
+	if t.t.invalid() {
+		panic("Tran method called on a deleted translator")
+	}
+	if t.t.phase != tranModel && t.t.phase != tranData {
+		return ErrTranState
+	}
+	if err := t.call(C.MPL_GENERATE, fname, 0, nil); err != nil {
+		return err
+	}
+	t.t.phase = tranGenerated
+	return nil
+}
+
+// BuildProb erases p and fills it with the generated model. It
+// returns ErrTranState if the model has not been generated.
+func (t *Tran) BuildProb(p *Prob) error {
+	if t.t.invalid() {
+		panic("Tran method called on a deleted translator")
+	}
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if t.t.phase != tranGenerated {
+		return ErrTranState
+	}
+	c := newCall(t.t.w)
+	C.go_glp_mpl_build_prob(&c.c, t.t.t, p.p.p)
+	c.done()
+	t.t.m, t.t.n = p.NumRows(), p.NumCols()
+	return nil
+}
+
+// Postsolve copies the solution of type sol (glpk.SOL, glpk.IPT or
+// glpk.MIP) from p (which should be built with BuildProb() and then
+// solved) to the translator and executes the model statements which
+// follow the solve statement (such as display, printf or table
+// statements). Postsolve may be called only once. On error (which is
+// an instance of *MPLError) the translator can only be deleted.
+func (t *Tran) Postsolve(p *Prob, sol SolType) error {
+	if t.t.invalid() {
+		panic("Tran method called on a deleted translator")
+	}
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if t.t.phase != tranGenerated {
+		return ErrTranState
+	}
+	if p.NumRows() != t.t.m || p.NumCols() != t.t.n {
+		return errors.New("glpk: problem does not match the generated model")
+	}
+	if sol != SOL && sol != IPT && sol != MIP {
+		return errors.New("glpk: invalid solution type")
+	}
+	if err := t.call(C.MPL_POSTSOLVE, "", int(sol), p); err != nil {
+		return err
+	}
+	t.t.phase = tranPostsolved
+	return nil
+}
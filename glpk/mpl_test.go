@@ -0,0 +1,145 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sampleModel is the problem from TestExample written in MathProg.
+const sampleModel = `
+set I := 0..2;
+param c{I};
+var x{I} >= 0;
+maximize Z: sum{i in I} c[i] * x[i];
+p: x[0] + x[1] + x[2] <= 100;
+q: 10 * x[0] + 4 * x[1] + 5 * x[2] <= 600;
+r: 2 * x[0] + 2 * x[1] + 6 * x[2] <= 300;
+solve;
+printf{i in I} "x%d = %.4f\n", i, x[i] > OUT;
+end;
+`
+
+const sampleData = `
+data;
+param c := 0 10, 1 6, 2 4;
+end;
+`
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMathProg(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glpk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "out.txt")
+	model := strings.Replace(sampleModel, "OUT", `"`+out+`"`, 1)
+	writeFiles(t, dir, map[string]string{"sample.mod": model, "sample.dat": sampleData})
+
+	tran := NewTran()
+	if err := tran.ReadModel(filepath.Join(dir, "sample.mod"), false); err != nil {
+		t.Fatalf("ReadModel error: %v", err)
+	}
+	if err := tran.ReadData(filepath.Join(dir, "sample.dat")); err != nil {
+		t.Fatalf("ReadData error: %v", err)
+	}
+	lp := New()
+	if err := tran.BuildProb(lp); err != ErrTranState {
+		t.Errorf("Expected %v before Generate but got %v", ErrTranState, err)
+	}
+	if err := tran.Generate(""); err != nil {
+		t.Fatalf("Generate error: %v", err)
+	}
+	if err := tran.BuildProb(lp); err != nil {
+		t.Fatalf("BuildProb error: %v", err)
+	}
+	if lp.NumRows() != 3 || lp.NumCols() != 3 {
+		t.Errorf("Got %d x %d problem expected 3 x 3", lp.NumRows(), lp.NumCols())
+	}
+	smcp := NewSmcp()
+	smcp.SetMsgLev(MSG_ERR)
+	if err := lp.Simplex(smcp); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	CheckClose(t, lp.ObjVal(), 733+1.0/3)
+	if err := tran.Postsolve(lp, SOL); err != nil {
+		t.Fatalf("Postsolve error: %v", err)
+	}
+	if err := tran.Postsolve(lp, SOL); err != ErrTranState {
+		t.Errorf("Expected %v on second Postsolve but got %v", ErrTranState, err)
+	}
+	data, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "x0 = 33.3333\nx1 = 66.6667\nx2 = 0.0000\n"
+	if string(data) != expected {
+		t.Errorf("Got printf output %#v expected %#v", string(data), expected)
+	}
+	lp.Delete()
+	tran.Delete()
+	tran.Delete()
+}
+
+func TestMathProgError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glpk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fname := filepath.Join(dir, "bad.mod")
+	writeFiles(t, dir, map[string]string{"bad.mod": "var x >= 0;\nmaximize z: x + ;\nend;\n"})
+	tran := NewTran()
+	err = tran.ReadModel(fname, false)
+	e, ok := err.(*MPLError)
+	if !ok {
+		t.Fatalf("Expected *MPLError but got %#v", err)
+	}
+	if e.File != fname || e.Line != 2 || e.Msg == "" {
+		t.Errorf("Got error %#v expected error in %s at line 2", e, fname)
+	}
+	if err := tran.Generate(""); err != ErrTranState {
+		t.Errorf("Expected %v after an error but got %v", ErrTranState, err)
+	}
+	tran.Delete()
+
+	// semantic error detected during generation: n has no value and
+	// is only referenced by the constraint at line 3
+	writeFiles(t, dir, map[string]string{"bad.mod": "param n;\nvar x{1..3};\ns.t. c: sum{i in 1..n} x[i] >= 0;\nend;\n"})
+	tran = NewTran()
+	if err := tran.ReadModel(fname, false); err != nil {
+		t.Fatalf("ReadModel error: %v", err)
+	}
+	err = tran.Generate("")
+	if e, ok := err.(*MPLError); !ok || e.Line != 3 {
+		t.Errorf("Expected *MPLError at line 3 but got %#v", err)
+	}
+	tran.Delete()
+}
@@ -0,0 +1,126 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import "errors"
+
+// #include <glpk.h>
+// #include "guard.h"
+//
+// #if GLPK_AT_LEAST(4, 47)
+// static int has_sat(void) { return 1; }
+// #define check_cnfsat glp_check_cnfsat
+// #define minisat1 glp_minisat1
+// #define intfeas1 glp_intfeas1
+// #else
+// static int has_sat(void) { return 0; }
+// #define check_cnfsat(P) 1
+// #define minisat1(P) GLP_EFAIL
+// #define intfeas1(P, use_bound, obj_bound) GLP_EFAIL
+// #endif
+//
+// GUARDED(int, go_glp_check_cnfsat, (gcall *c, glp_prob *P), check_cnfsat(P))
+// GUARDED(int, go_glp_minisat1, (gcall *c, glp_prob *P), minisat1(P))
+// GUARDED(int, go_glp_intfeas1, (gcall *c, glp_prob *P, int use_bound, int obj_bound), intfeas1(P, use_bound, obj_bound))
+import "C"
+
+// ErrNotCNFSAT is returned by CheckCNFSAT if the problem is not a
+// CNF-SAT problem.
+var ErrNotCNFSAT = errors.New("glpk: problem is not a CNF-SAT problem")
+
+// CheckCNFSAT checks whether the problem is a CNF-SAT problem, i.e. a
+// MIP without objective in which all columns are binary and each row
+// represents a clause: it is of the form
+//
+//     sum(j in J+) x[j] + sum(j in J-) (1 - x[j]) >= 1
+//
+// where J+ and J- are indices of the positive and negative literals.
+// It returns nil, ErrNotCNFSAT or ErrUnsupported if GLPK is older than
+// 4.47.
+func (p *Prob) CheckCNFSAT() error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if C.has_sat() == 0 {
+		return ErrUnsupported
+	}
+	c := newCall(p.p.w)
+	ret := C.go_glp_check_cnfsat(&c.c, p.p.p)
+	if err := c.err(); err != nil {
+		return err
+	}
+	if ret != 0 {
+		return ErrNotCNFSAT
+	}
+	return nil
+}
+
+// Minisat1 solves a CNF-SAT problem (see CheckCNFSAT) with the MiniSat
+// solver. If it returns nil the result is available with MipStatus()
+// (glpk.OPT if the problem is satisfiable or glpk.NOFEAS if it is not)
+// and the satisfying assignment with MipColVal(). Otherwise it returns
+// an OptError (glpk.EDATA if the problem is not a CNF-SAT problem or
+// glpk.EFAIL if the solver failed) or ErrUnsupported if GLPK is older
+// than 4.47.
+func (p *Prob) Minisat1() error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if C.has_sat() == 0 {
+		return ErrUnsupported
+	}
+	c := newCall(p.p.w)
+	ret := C.go_glp_minisat1(&c.c, p.p.p)
+	if err := c.err(); err != nil {
+		return err
+	}
+	if ret != 0 {
+		return OptError(ret)
+	}
+	return nil
+}
+
+// Intfeas1 searches for an integer feasible solution of a problem in
+// which all columns are binary (or fixed) and all constraint and
+// objective coefficients are integer. The problem is translated to
+// CNF-SAT and solved with MiniSat. If useBound is true only solutions
+// with the objective value not worse than objBound are searched for.
+// If it returns nil the result is available with MipStatus()
+// (glpk.FEAS if a solution was found or glpk.NOFEAS if there is no
+// such solution) and the solution with MipColVal() and MipObjVal().
+// Otherwise it returns an OptError (glpk.EDATA if the problem is not
+// of this form, glpk.ERANGE if an integer overflow occurred or
+// glpk.EFAIL if the solver failed) or ErrUnsupported if GLPK is older
+// than 4.47.
+func (p *Prob) Intfeas1(useBound bool, objBound int) error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if C.has_sat() == 0 {
+		return ErrUnsupported
+	}
+	c := newCall(p.p.w)
+	ret := C.go_glp_intfeas1(&c.c, p.p.p, glpBool(useBound), C.int(objBound))
+	if err := c.err(); err != nil {
+		return err
+	}
+	if ret != 0 {
+		return OptError(ret)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// (x1 or not x2) and (x2 or x3) and (not x1 or not x3)
+const satCNF = `c satisfiable
+p cnf 3 3
+1 -2 0
+2 3 0
+-1 -3 0
+`
+
+// x1 and not x1
+const unsatCNF = `c unsatisfiable
+p cnf 1 2
+1 0
+-1 0
+`
+
+var satClauses = [][]int{{1, -2}, {2, 3}, {-1, -3}}
+
+func readCNF(t *testing.T, cnf string) *Prob {
+	lp := New()
+	lp.SetTermWriter(ioutil.Discard)
+	if err := lp.ReadCNFSATFrom(strings.NewReader(cnf)); err == ErrUnsupported {
+		lp.Delete()
+		t.Skip("CNF-SAT is not supported")
+	} else if err != nil {
+		t.Fatalf("ReadCNFSATFrom error: %v", err)
+	}
+	return lp
+}
+
+// checkClauses checks that the MIP solution of lp satisfies satClauses.
+func checkClauses(t *testing.T, lp *Prob) {
+	for _, clause := range satClauses {
+		sat := false
+		for _, l := range clause {
+			if l > 0 && lp.MipColVal(l) == 1 || l < 0 && lp.MipColVal(-l) == 0 {
+				sat = true
+			}
+		}
+		if !sat {
+			t.Errorf("Clause %v not satisfied", clause)
+		}
+	}
+}
+
+func TestMinisat1(t *testing.T) {
+	lp := readCNF(t, satCNF)
+	defer lp.Delete()
+	if lp.NumRows() != 3 || lp.NumCols() != 3 {
+		t.Fatalf("Got problem %d x %d expected 3 x 3", lp.NumRows(), lp.NumCols())
+	}
+	if err := lp.CheckCNFSAT(); err != nil {
+		t.Fatalf("CheckCNFSAT error: %v", err)
+	}
+	if err := lp.Minisat1(); err != nil {
+		t.Fatalf("Minisat1 error: %v", err)
+	}
+	if lp.MipStatus() != OPT {
+		t.Fatalf("Got status %d expected OPT", lp.MipStatus())
+	}
+	checkClauses(t, lp)
+
+	var buf bytes.Buffer
+	if err := lp.WriteCNFSATTo(&buf); err != nil {
+		t.Fatalf("WriteCNFSATTo error: %v", err)
+	}
+	lp2 := readCNF(t, buf.String())
+	defer lp2.Delete()
+	if lp2.NumRows() != 3 || lp2.NumCols() != 3 {
+		t.Errorf("Got problem %d x %d after writing and reading", lp2.NumRows(), lp2.NumCols())
+	}
+
+	unsat := readCNF(t, unsatCNF)
+	defer unsat.Delete()
+	if err := unsat.Minisat1(); err != nil {
+		t.Fatalf("Minisat1 error: %v", err)
+	}
+	if unsat.MipStatus() != NOFEAS {
+		t.Errorf("Got status %d expected NOFEAS", unsat.MipStatus())
+	}
+
+	p := sampleProb()
+	defer p.Delete()
+	p.SetTermWriter(ioutil.Discard)
+	if err := p.CheckCNFSAT(); err != ErrNotCNFSAT {
+		t.Errorf("CheckCNFSAT of LP returned %v expected ErrNotCNFSAT", err)
+	}
+	if err := p.Minisat1(); err != EDATA {
+		t.Errorf("Minisat1 of LP returned %v expected EDATA", err)
+	}
+	if err := p.WriteCNFSATTo(&buf); err == nil {
+		t.Errorf("WriteCNFSATTo of LP succeeded")
+	}
+}
+
+func TestIntfeas1(t *testing.T) {
+	lp := readCNF(t, satCNF)
+	defer lp.Delete()
+	if err := lp.Intfeas1(false, 0); err != nil {
+		t.Fatalf("Intfeas1 error: %v", err)
+	}
+	if lp.MipStatus() != FEAS {
+		t.Fatalf("Got status %d expected FEAS", lp.MipStatus())
+	}
+	checkClauses(t, lp)
+
+	// minimize x1 + x2 + x3: x3 = 1 is the only solution with value 1
+	for j := 1; j <= 3; j++ {
+		lp.SetObjCoef(j, 1)
+	}
+	if err := lp.Intfeas1(true, 2); err != nil {
+		t.Fatalf("Intfeas1 error: %v", err)
+	}
+	if lp.MipStatus() != FEAS || lp.MipObjVal() > 2 {
+		t.Errorf("Got status %d with objective %g expected FEAS with at most 2", lp.MipStatus(), lp.MipObjVal())
+	}
+	checkClauses(t, lp)
+	if err := lp.Intfeas1(true, 0); err != nil {
+		t.Fatalf("Intfeas1 error: %v", err)
+	}
+	if lp.MipStatus() != NOFEAS {
+		t.Errorf("Got status %d expected NOFEAS for objective bound 0", lp.MipStatus())
+	}
+}
@@ -0,0 +1,327 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+	"unsafe"
+)
+
+// #include <glpk.h>
+// #include <stdlib.h>
+// #include "guard.h"
+//
+// GUARDED_VOID(go_glp_analyze_bound, (gcall *c, glp_prob *P, int k, double *value1, int *var1, double *value2, int *var2), glp_analyze_bound(P, k, value1, var1, value2, var2))
+// GUARDED_VOID(go_glp_analyze_coef, (gcall *c, glp_prob *P, int k, double *coef1, int *var1, double *value1, double *coef2, int *var2, double *value2), glp_analyze_coef(P, k, coef1, var1, value1, coef2, var2, value2))
+// GUARDED(int, go_glp_print_ranges, (gcall *c, glp_prob *P, const char *fname), glp_print_ranges(P, 0, NULL, 0, fname))
+import "C"
+
+// Sensitivity analysis refers to variables by their ordinal numbers:
+// k = 1..m for rows (auxiliary variables) and k = m+1..m+n for
+// columns (structural variables), where m is the number of rows and
+// n the number of columns.
+
+var (
+	// ErrNotOptimal is returned by sensitivity analysis if the
+	// basic solution is not optimal.
+	ErrNotOptimal = errors.New("glpk: optimal basic solution required")
+	// ErrNoFactorization is returned by sensitivity analysis if
+	// the basis factorization does not exist (e.g. because the
+	// problem was modified after solving it).
+	ErrNoFactorization = errors.New("glpk: basis factorization required")
+)
+
+// BoundRange is the result of the sensitivity analysis of the active
+// bound of a non-basic variable (see Prob.AnalyzeBound).
+type BoundRange struct {
+	Value1 float64 // minimal value of the active bound at which the basis remains primal feasible (-Inf if unlimited)
+	Var1   int     // ordinal number of the basic variable which limits Value1 (0 if none)
+	Obj1   float64 // objective value at Value1
+	Value2 float64 // maximal value of the active bound at which the basis remains primal feasible (+Inf if unlimited)
+	Var2   int     // ordinal number of the basic variable which limits Value2 (0 if none)
+	Obj2   float64 // objective value at Value2
+}
+
+// CoefRange is the result of the sensitivity analysis of an
+// objective coefficient (see Prob.AnalyzeCoef).
+type CoefRange struct {
+	Coef1  float64 // minimal value of the coefficient at which the basis remains dual feasible (-Inf if unlimited)
+	Var1   int     // ordinal number of the non-basic variable which limits Coef1 (0 if none)
+	Value1 float64 // value of the variable in the adjacent basis when the coefficient goes below Coef1
+	Obj1   float64 // objective value at Coef1
+	Coef2  float64 // maximal value of the coefficient at which the basis remains dual feasible (+Inf if unlimited)
+	Var2   int     // ordinal number of the non-basic variable which limits Coef2 (0 if none)
+	Value2 float64 // value of the variable in the adjacent basis when the coefficient goes above Coef2
+	Obj2   float64 // objective value at Coef2
+}
+
+// unbounded converts GLPK's +/-DBL_MAX to +/-Inf.
+func unbounded(v float64) float64 {
+	if v >= math.MaxFloat64 {
+		return math.Inf(1)
+	} else if v <= -math.MaxFloat64 {
+		return math.Inf(-1)
+	}
+	return v
+}
+
+// objAt returns the objective value obj changed by a*b (where either
+// factor may be infinite).
+func objAt(obj, a, b float64) float64 {
+	if a == 0 || b == 0 {
+		return obj
+	}
+	return obj + a*b
+}
+
+// checkBasis checks that sensitivity analysis may be performed.
+func (p *Prob) checkBasis() error {
+	if p.PrimStat() != FEAS || p.DualStat() != FEAS {
+		return ErrNotOptimal
+	}
+	if p.NumRows() > 0 && C.glp_bf_exists(p.p.p) == 0 {
+		return ErrNoFactorization
+	}
+	return nil
+}
+
+// checkSens checks that sensitivity analysis of the variable k may be
+// performed by op.
+func (p *Prob) checkSens(op string, k int) error {
+	if err := p.checkBasis(); err != nil {
+		return err
+	}
+	return checkIndex(op, "variable", k, p.NumRows()+p.NumCols())
+}
+
+// varInfo returns the status, value, reduced cost and objective
+// coefficient of the variable k.
+func (p *Prob) varInfo(k int) (stat VarStat, value, dual, coef float64) {
+	m := p.NumRows()
+	if k <= m {
+		return p.RowStat(k), p.RowPrim(k), p.RowDual(k), 0
+	}
+	return p.ColStat(k - m), p.ColPrim(k - m), p.ColDual(k - m), p.ObjCoef(k - m)
+}
+
+// AnalyzeBound performs sensitivity analysis of the active bound of
+// the non-basic variable with ordinal number k (see above). It
+// returns ErrNotOptimal or ErrNoFactorization if the optimal basic
+// solution (as found by Simplex) is not available, and an
+// *IndexError if k is out of range.
+func (p *Prob) AnalyzeBound(k int) (*BoundRange, error) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if err := p.checkSens("AnalyzeBound", k); err != nil {
+		return nil, err
+	}
+	stat, value, dual, _ := p.varInfo(k)
+	if stat == BS {
+		return nil, fmt.Errorf("glpk: AnalyzeBound: variable %d is basic", k)
+	}
+	var value1, value2 C.double
+	var var1, var2 C.int
+	c := newCall(p.p.w)
+	C.go_glp_analyze_bound(&c.c, p.p.p, C.int(k), &value1, &var1, &value2, &var2)
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	r := &BoundRange{
+		Value1: unbounded(float64(value1)),
+		Var1:   int(var1),
+		Value2: unbounded(float64(value2)),
+		Var2:   int(var2),
+	}
+	obj := p.ObjVal()
+	r.Obj1 = objAt(obj, dual, r.Value1-value)
+	r.Obj2 = objAt(obj, dual, r.Value2-value)
+	return r, nil
+}
+
+// AnalyzeCoef performs sensitivity analysis of the objective
+// coefficient of the variable with ordinal number k (see above). It
+// returns ErrNotOptimal or ErrNoFactorization if the optimal basic
+// solution (as found by Simplex) is not available, and an
+// *IndexError if k is out of range.
+func (p *Prob) AnalyzeCoef(k int) (*CoefRange, error) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if err := p.checkSens("AnalyzeCoef", k); err != nil {
+		return nil, err
+	}
+	_, value, _, coef := p.varInfo(k)
+	var coef1, value1, coef2, value2 C.double
+	var var1, var2 C.int
+	c := newCall(p.p.w)
+	C.go_glp_analyze_coef(&c.c, p.p.p, C.int(k), &coef1, &var1, &value1, &coef2, &var2, &value2)
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	r := &CoefRange{
+		Coef1:  unbounded(float64(coef1)),
+		Var1:   int(var1),
+		Value1: unbounded(float64(value1)),
+		Coef2:  unbounded(float64(coef2)),
+		Var2:   int(var2),
+		Value2: unbounded(float64(value2)),
+	}
+	obj := p.ObjVal()
+	r.Obj1 = objAt(obj, r.Coef1-coef, value)
+	r.Obj2 = objAt(obj, r.Coef2-coef, value)
+	return r, nil
+}
+
+// VarRange is the sensitivity analysis of a single variable (see
+// Prob.Ranges).
+type VarRange struct {
+	Var      int     // ordinal number of the variable
+	Name     string  // row or column name
+	Stat     VarStat // status in the basic solution
+	Value    float64 // primal value (activity)
+	Marginal float64 // dual value (reduced cost)
+	Type     BndsType
+	LB, UB   float64
+	ObjCoef  float64     // objective coefficient (0 for rows)
+	Bound    *BoundRange // analysis of the active bound (nil for basic variables)
+	Coef     CoefRange   // analysis of the objective coefficient
+}
+
+// Ranges is the sensitivity analysis report of all the variables of
+// a problem (see Prob.Ranges).
+type Ranges struct {
+	ObjVal float64
+	Rows   []VarRange // Rows[i-1] is the analysis of i-th row
+	Cols   []VarRange // Cols[j-1] is the analysis of j-th column
+}
+
+// Ranges performs sensitivity analysis of all the variables of the
+// problem. It returns ErrNotOptimal or ErrNoFactorization if the
+// optimal basic solution (as found by Simplex) is not available.
+func (p *Prob) Ranges() (*Ranges, error) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if err := p.checkBasis(); err != nil {
+		return nil, err
+	}
+	m, n := p.NumRows(), p.NumCols()
+	r := &Ranges{ObjVal: p.ObjVal(), Rows: make([]VarRange, m), Cols: make([]VarRange, n)}
+	for k := 1; k <= m+n; k++ {
+		var v *VarRange
+		if k <= m {
+			v = &r.Rows[k-1]
+			v.Name, v.Type, v.LB, v.UB = p.RowName(k), p.RowType(k), p.RowLB(k), p.RowUB(k)
+		} else {
+			v = &r.Cols[k-m-1]
+			v.Name, v.Type, v.LB, v.UB = p.ColName(k-m), p.ColType(k-m), p.ColLB(k-m), p.ColUB(k-m)
+		}
+		v.Var = k
+		v.Stat, v.Value, v.Marginal, v.ObjCoef = p.varInfo(k)
+		if v.Stat != BS {
+			b, err := p.AnalyzeBound(k)
+			if err != nil {
+				return nil, err
+			}
+			v.Bound = b
+		}
+		c, err := p.AnalyzeCoef(k)
+		if err != nil {
+			return nil, err
+		}
+		v.Coef = *c
+	}
+	return r, nil
+}
+
+var varStatNames = map[VarStat]string{BS: "BS", NL: "NL", NU: "NU", NF: "NF", NS: "NS"}
+
+// WriteTo writes the report as a plain text table to w. For basic
+// variables the activity range is given by the values of the variable
+// at the limits of the objective coefficient range.
+func (r *Ranges) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	tw := tabwriter.NewWriter(cw, 0, 8, 1, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "Objective value:\t%g\t\n\n", r.ObjVal)
+	fmt.Fprint(tw, "No.\tName\tSt\tActivity\tMarginal\tLower bound\tUpper bound\t"+
+		"Activity range\tObj value\tLimiting var\tObj coef\tObj coef range\tObj value\tLimiting var\t\n")
+	for _, vs := range [][]VarRange{r.Rows, r.Cols} {
+		for _, v := range vs {
+			act1, obj1, var1 := v.Coef.Value1, v.Coef.Obj1, v.Coef.Var1
+			act2, obj2, var2 := v.Coef.Value2, v.Coef.Obj2, v.Coef.Var2
+			if v.Bound != nil {
+				act1, obj1, var1 = v.Bound.Value1, v.Bound.Obj1, v.Bound.Var1
+				act2, obj2, var2 = v.Bound.Value2, v.Bound.Obj2, v.Bound.Var2
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%g\t%g\t%g\t%g\t%g\t%g\t%d\t%g\t%g\t%g\t%d\t\n",
+				v.Var, v.Name, varStatNames[v.Stat], v.Value, v.Marginal, v.LB, v.UB,
+				act1, obj1, var1, v.ObjCoef, v.Coef.Coef1, v.Coef.Obj1, v.Coef.Var1)
+			fmt.Fprintf(tw, "\t\t\t\t\t\t\t%g\t%g\t%d\t\t%g\t%g\t%d\t\n",
+				act2, obj2, var2, v.Coef.Coef2, v.Coef.Obj2, v.Coef.Var2)
+		}
+	}
+	err := tw.Flush()
+	return cw.n, err
+}
+
+// countingWriter counts bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// PrintRanges writes the sensitivity analysis report of all the
+// variables in GLPK format to a file (see also Ranges). It returns
+// ErrNotOptimal or ErrNoFactorization if the optimal basic solution
+// (as found by Simplex) is not available, or a *FileError.
+func (p *Prob) PrintRanges(fname string) error {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	if err := p.checkBasis(); err != nil {
+		return err
+	}
+	s := C.CString(fname)
+	defer C.free(unsafe.Pointer(s))
+	c := newCall(p.p.w).collect()
+	ret := C.go_glp_print_ranges(&c.c, p.p.p, s)
+	if err := c.err(); err != nil {
+		return err
+	}
+	if ret != 0 {
+		return &FileError{"print ranges", fname, c.out}
+	}
+	return nil
+}
+
+// PrintRangesTo writes the sensitivity analysis report in GLPK format
+// to w. See also PrintRanges().
+func (p *Prob) PrintRangesTo(w io.Writer) error {
+	return writeTo(w, p.PrintRanges)
+}
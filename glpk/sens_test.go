@@ -0,0 +1,114 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeBound(t *testing.T) {
+	lp := sampleProb()
+	defer lp.Delete()
+	if _, err := lp.AnalyzeBound(1); err != ErrNotOptimal {
+		t.Errorf("expected ErrNotOptimal but got %v", err)
+	}
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	r, err := lp.AnalyzeBound(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	CheckClose(t, r.Value1, 60)
+	CheckClose(t, r.Obj1, 600)
+	CheckClose(t, r.Value2, 150)
+	CheckClose(t, r.Obj2, 900)
+	if r.Var1 != 5 {
+		t.Errorf("expected limiting variable 5 (x1) but got %d", r.Var1)
+	}
+	if _, err := lp.AnalyzeBound(4); err == nil {
+		t.Errorf("expected error for a basic variable")
+	}
+	if _, err := lp.AnalyzeBound(7); err == nil {
+		t.Errorf("expected error for an out of range variable")
+	} else if _, ok := err.(*IndexError); !ok {
+		t.Errorf("expected *IndexError but got %#v", err)
+	}
+}
+
+func TestAnalyzeCoef(t *testing.T) {
+	lp := sampleProb()
+	defer lp.Delete()
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	r, err := lp.AnalyzeCoef(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(r.Coef1, -1) {
+		t.Errorf("expected -Inf but got %g", r.Coef1)
+	}
+	CheckClose(t, r.Coef2, 20.0/3)
+	CheckClose(t, r.Obj2, 733.33333333333333)
+
+	lp.SetMatRow(3, []int32{0, 1}, []float64{0, 1})
+	if _, err := lp.AnalyzeCoef(6); err != ErrNoFactorization {
+		t.Errorf("expected ErrNoFactorization but got %v", err)
+	}
+}
+
+func TestRanges(t *testing.T) {
+	lp := sampleProb()
+	defer lp.Delete()
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	r, err := lp.Ranges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Rows) != 3 || len(r.Cols) != 3 {
+		t.Fatalf("expected 3 rows and 3 columns but got %d and %d", len(r.Rows), len(r.Cols))
+	}
+	if r.Rows[0].Name != "p" || r.Rows[0].Bound == nil || r.Rows[2].Bound != nil {
+		t.Errorf("unexpected row analysis: %#v", r.Rows)
+	}
+	CheckClose(t, r.Rows[0].Bound.Value1, 60)
+	if r.Cols[2].Var != 6 || r.Cols[2].ObjCoef != 4 {
+		t.Errorf("unexpected column analysis: %#v", r.Cols[2])
+	}
+	var buf bytes.Buffer
+	n, err := r.WriteTo(&buf)
+	if err != nil || n != int64(buf.Len()) {
+		t.Errorf("WriteTo returned %d, %v", n, err)
+	}
+	if !strings.Contains(buf.String(), "x2") {
+		t.Errorf("column x2 missing in the report:\n%s", buf.String())
+	}
+	buf.Reset()
+	if err := lp.PrintRangesTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "x2") {
+		t.Errorf("column x2 missing in the GLPK report:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,80 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+#include <stdlib.h>
+#include <string.h>
+#include <glpk.h>
+#include "term.h"
+#include "_cgo_export.h"
+
+static void termbuf_append(termbuf *b, const char *s) {
+	size_t n = strlen(s);
+	if (b->len + n + 1 > b->size) {
+		size_t size = 2 * b->size + n + 1;
+		char *buf = realloc(b->buf, size);
+		if (buf == NULL)
+			return;
+		b->buf = buf;
+		b->size = size;
+	}
+	memcpy(b->buf + b->len, s, n + 1);
+	b->len += n;
+}
+
+// TERM_TAIL is the amount of output kept in TERM_COLLECT_TAIL mode.
+#define TERM_TAIL 4096
+
+// termbuf_trim drops all but the last TERM_TAIL bytes of b.
+static void termbuf_trim(termbuf *b) {
+	if (b->len > 2 * TERM_TAIL) {
+		memmove(b->buf, b->buf + b->len - TERM_TAIL, TERM_TAIL + 1);
+		b->len = TERM_TAIL;
+	}
+}
+
+static int term_hook(void *info, const char *s) {
+	termctx *c = info;
+	if (c->collect) {
+		termbuf_append(&c->buf, s);
+		if (c->collect == TERM_COLLECT_TAIL)
+			termbuf_trim(&c->buf);
+	}
+	if (c->cfg.off)
+		return 1;
+	if (c->cfg.w != 0) {
+		goTermWrite(c->cfg.w, (char *)s);
+		return 1;
+	}
+	return 0;
+}
+
+void term_begin(termctx *c, termcfg cfg, int collect) {
+	c->cfg = cfg;
+	c->collect = collect;
+	c->buf.buf = NULL;
+	c->buf.len = c->buf.size = 0;
+	term_resume(c);
+}
+
+void term_resume(termctx *c) {
+	glp_term_hook(term_hook, c);
+}
+
+char *term_end(termctx *c) {
+	glp_term_hook(NULL, NULL);
+	return c->buf.buf;
+}
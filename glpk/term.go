@@ -0,0 +1,177 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// #include "term.h"
+import "C"
+
+// Terminal output of GLPK (such as the solver progress, messages of
+// file readers and writers, and output of MathProg display and printf
+// statements) goes to stdout unless redirected with SetTermWriter()
+// (for all problems) or with Prob.SetTermWriter(),
+// Tran.SetTermWriter() and Graph.SetTermWriter() (for a single
+// problem, translator or graph). Errors returned by a writer are
+// ignored and if its Write method panics the panic is recovered and
+// the output of that call is dropped (the operation continues).
+//
+// GLPK keeps its terminal settings per thread (if it was built with
+// thread local storage support, which is the default) so the package
+// does not change them globally but sets them up for the duration of
+// every operation which may produce output. Thus solving different
+// problems concurrently with different writers does not mix their
+// output.
+var term struct {
+	sync.Mutex         // serializes SetTermWriter
+	w          uintptr // termWriter of the global writer or 0 for stdout (atomic)
+	off        uint32  // whether the output is disabled (atomic)
+}
+
+// termWriter identifies a writer registered with newTermWriter (0
+// means no writer). Writers are registered once (by SetTermWriter)
+// rather than for every operation and C code refers to them by id.
+type termWriter uintptr
+
+var (
+	termWriters    sync.Map // termWriter -> io.Writer
+	lastTermWriter uintptr
+)
+
+// newTermWriter registers w (if not nil).
+func newTermWriter(w io.Writer) termWriter {
+	if w == nil {
+		return 0
+	}
+	id := termWriter(atomic.AddUintptr(&lastTermWriter, 1))
+	termWriters.Store(id, w)
+	return id
+}
+
+// release unregisters the writer. Output of operations still using
+// it is dropped.
+func (id termWriter) release() {
+	if id != 0 {
+		termWriters.Delete(id)
+	}
+}
+
+// lockedWriter serializes writes of concurrent operations to the
+// global writer.
+type lockedWriter struct {
+	sync.Mutex
+	w io.Writer
+}
+
+func (w *lockedWriter) Write(b []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+	return w.w.Write(b)
+}
+
+// SetTermWriter directs GLPK terminal output of all problems and
+// translators which have no writer of their own to w. Writes to w are
+// serialized. If w is nil the output goes to stdout (default). A panic
+// in w.Write is recovered and the output of that call is dropped.
+func SetTermWriter(w io.Writer) {
+	term.Lock()
+	defer term.Unlock()
+	var id termWriter
+	if w != nil {
+		id = newTermWriter(&lockedWriter{w: w})
+	}
+	termWriter(atomic.SwapUintptr(&term.w, uintptr(id))).release()
+}
+
+// TermOut enables or disables all GLPK terminal output (default:
+// enabled), including output sent to writers set with
+// SetTermWriter(), Prob.SetTermWriter() and Tran.SetTermWriter(). It
+// returns the previous setting. It is the analogue of glp_term_out
+// which works for all threads.
+func TermOut(on bool) bool {
+	var off uint32
+	if !on {
+		off = 1
+	}
+	return atomic.SwapUint32(&term.off, off) == 0
+}
+
+// termConfig returns terminal configuration for an operation whose
+// output should go to w (or, if w is 0, to the global writer).
+func termConfig(w termWriter) C.termcfg {
+	if atomic.LoadUint32(&term.off) != 0 {
+		return C.termcfg{off: 1}
+	}
+	if w == 0 {
+		w = termWriter(atomic.LoadUintptr(&term.w))
+	}
+	return C.termcfg{w: C.uintptr_t(w)}
+}
+
+//export goTermWrite
+func goTermWrite(id C.uintptr_t, s *C.char) {
+	// a panic must not unwind through the C frames of GLPK
+	defer func() { recover() }()
+	if w, ok := termWriters.Load(termWriter(id)); ok {
+		w.(io.Writer).Write([]byte(C.GoString(s)))
+	}
+}
+
+// SetTermWriter directs GLPK terminal output produced while operating
+// on the problem (solvers and file readers/writers) to w. If w is nil
+// the output goes to the global destination (see
+// glpk.SetTermWriter()). A panic in w.Write is recovered and the
+// output of that call is dropped.
+func (p *Prob) SetTermWriter(w io.Writer) {
+	if p.p.invalid() {
+		panic("Prob method called on a deleted problem")
+	}
+	p.p.w.release()
+	p.p.w = newTermWriter(w)
+}
+
+// SetTermWriter directs GLPK terminal output produced by the
+// translator (including output of display and printf statements
+// executed by Generate() and Postsolve()) to w. If w is nil the
+// output goes to the global destination (see glpk.SetTermWriter()).
+// A panic in w.Write is recovered and the output of that call is
+// dropped.
+func (t *Tran) SetTermWriter(w io.Writer) {
+	if t.t.invalid() {
+		panic("Tran method called on a deleted translator")
+	}
+	t.t.w.release()
+	t.t.w = newTermWriter(w)
+}
+
+// SetTermWriter directs GLPK terminal output produced while operating
+// on the graph (algorithms and file readers/writers) to w. If w is nil
+// the output goes to the global destination (see
+// glpk.SetTermWriter()). A panic in w.Write is recovered and the
+// output of that call is dropped.
+func (g *Graph) SetTermWriter(w io.Writer) {
+	if g.g.invalid() {
+		panic("Graph method called on a deleted graph")
+	}
+	g.g.w.release()
+	g.g.w = newTermWriter(w)
+}
@@ -0,0 +1,62 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+#ifndef GO_GLPK_TERM_H
+#define GO_GLPK_TERM_H
+
+#include <stddef.h>
+#include <stdint.h>
+
+// termcfg specifies where GLPK terminal output of an operation goes.
+typedef struct {
+	uintptr_t w; // id of a Go io.Writer (see termWriter) or 0 for stdout
+	int off;     // if nonzero the output is suppressed
+} termcfg;
+
+// termbuf collects terminal output of GLPK so that diagnostic
+// messages may be returned as errors.
+typedef struct {
+	char *buf;
+	size_t len, size;
+} termbuf;
+
+// termctx is the state of term_hook during a single operation.
+typedef struct {
+	termcfg cfg;
+	int collect; // whether to collect the output in buf (see below)
+	termbuf buf;
+} termctx;
+
+// Values of collect: TERM_COLLECT collects all the output while
+// TERM_COLLECT_TAIL keeps only its last few kilobytes (enough for an
+// error message).
+enum { TERM_COLLECT = 1, TERM_COLLECT_TAIL = 2 };
+
+// term_begin installs (in the GLPK environment of the calling
+// thread) a terminal hook directing output according to cfg and, if
+// collect is nonzero, also collecting it.
+void term_begin(termctx *c, termcfg cfg, int collect);
+
+// term_resume reinstalls the terminal hook of c (after it was
+// replaced by a nested operation).
+void term_resume(termctx *c);
+
+// term_end uninstalls the terminal hook and returns collected output
+// (or NULL) which should be freed by the caller.
+char *term_end(termctx *c);
+
+#endif
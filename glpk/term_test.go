@@ -0,0 +1,172 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestProbTermWriter(t *testing.T) {
+	lp := sampleProb()
+	var buf bytes.Buffer
+	lp.SetTermWriter(&buf)
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "OPTIMAL") {
+		t.Errorf("Simplex output not captured: %#v", buf.String())
+	}
+
+	buf.Reset()
+	prev := TermOut(false)
+	if !prev {
+		t.Errorf("Terminal output was disabled by default")
+	}
+	lp.Simplex(nil)
+	TermOut(true)
+	if buf.Len() != 0 {
+		t.Errorf("Got output %#v while terminal output was disabled", buf.String())
+	}
+	lp.Delete()
+}
+
+type panicWriter struct{}
+
+func (panicWriter) Write(b []byte) (int, error) {
+	panic("write failed")
+}
+
+func TestPanickingTermWriter(t *testing.T) {
+	lp := sampleProb()
+	defer lp.Delete()
+	lp.SetTermWriter(panicWriter{})
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	CheckClose(t, lp.ObjVal(), 733.33333333333333)
+}
+
+func TestGlobalTermWriter(t *testing.T) {
+	var buf, buf2 bytes.Buffer
+	SetTermWriter(&buf)
+	defer SetTermWriter(nil)
+	lp := sampleProb()
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "OPTIMAL") {
+		t.Errorf("Simplex output not captured: %#v", buf.String())
+	}
+	// writer of the problem takes precedence
+	buf.Reset()
+	lp.SetTermWriter(&buf2)
+	lp.Simplex(nil)
+	if buf.Len() != 0 || buf2.Len() == 0 {
+		t.Errorf("Got output %#v in global writer and %#v in problem writer", buf.String(), buf2.String())
+	}
+	lp.Delete()
+}
+
+func TestConcurrentTermWriters(t *testing.T) {
+	const n = 8
+	var wg sync.WaitGroup
+	bufs := make([]bytes.Buffer, n)
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			// GLPK memory of a problem belongs to the environment of
+			// the thread which allocated it
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+			// problem g has g+2 rows: x1 + x2 <= k for k=1..g+2
+			lp := New()
+			lp.SetObjDir(MAX)
+			lp.AddCols(2)
+			lp.SetObjCoef(1, 1)
+			lp.SetObjCoef(2, 1)
+			lp.AddRows(g + 2)
+			for i := 1; i <= g+2; i++ {
+				lp.SetRowBnds(i, UP, 0, float64(i))
+				lp.SetMatRow(i, []int32{0, 1, 2}, []float64{0, 1, 1})
+			}
+			lp.SetTermWriter(&bufs[g])
+			for k := 0; k < 20; k++ {
+				lp.Simplex(nil)
+			}
+			lp.Delete()
+		}(g)
+	}
+	wg.Wait()
+	for g := 0; g < n; g++ {
+		out := bufs[g].String()
+		if c := strings.Count(out, "OPTIMAL"); c != 20 {
+			t.Errorf("Goroutine %d: got %d solutions in output expected 20", g, c)
+		}
+		if c := strings.Count(out, fmt.Sprintf("%d rows,", g+2)); c != 20 {
+			t.Errorf("Goroutine %d: output of other problems in %#v", g, out)
+		}
+	}
+}
+
+func TestTranTermWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glpk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	model := strings.Replace(sampleModel, " > OUT", "", 1)
+	writeFiles(t, dir, map[string]string{"sample.mod": model, "sample.dat": sampleData})
+
+	var buf bytes.Buffer
+	tran := NewTran()
+	tran.SetTermWriter(&buf)
+	if err := tran.ReadModel(filepath.Join(dir, "sample.mod"), false); err != nil {
+		t.Fatalf("ReadModel error: %v", err)
+	}
+	if err := tran.ReadData(filepath.Join(dir, "sample.dat")); err != nil {
+		t.Fatalf("ReadData error: %v", err)
+	}
+	if err := tran.Generate(""); err != nil {
+		t.Fatalf("Generate error: %v", err)
+	}
+	lp := New()
+	tran.BuildProb(lp)
+	lp.SetTermWriter(ioutil.Discard)
+	if err := lp.Simplex(nil); err != nil {
+		t.Fatalf("Simplex error: %v", err)
+	}
+	buf.Reset()
+	if err := tran.Postsolve(lp, SOL); err != nil {
+		t.Fatalf("Postsolve error: %v", err)
+	}
+	expected := "x0 = 33.3333\nx1 = 66.6667\nx2 = 0.0000\n"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Got output %#v expected it to contain %#v", buf.String(), expected)
+	}
+	lp.Delete()
+	tran.Delete()
+}
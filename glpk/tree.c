@@ -0,0 +1,36 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+#include <stdint.h>
+#include <glpk.h>
+#include "guard.h"
+#include "tree.h"
+#include "_cgo_export.h"
+
+// ios_callback is the branch-and-cut callback of glp_intopt. The
+// handle of the Go callback state is passed in info.
+static void ios_callback(glp_tree *T, void *info) {
+	goIosCallback(T, (uintptr_t)info);
+	// glp_intopt can not continue if a GLPK routine called by the
+	// callback failed
+	guard_unwind();
+}
+
+void iocp_set_cb(glp_iocp *parm, uintptr_t h) {
+	parm->cb_func = ios_callback;
+	parm->cb_info = (void *)h;
+}
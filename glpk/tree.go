@@ -0,0 +1,287 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// #include <glpk.h>
+// #include <stdlib.h>
+// #include "guard.h"
+//
+// GUARDED(int, go_glp_ios_next_node, (gcall *c, glp_tree *T, int p), glp_ios_next_node(T, p))
+// GUARDED(int, go_glp_ios_prev_node, (gcall *c, glp_tree *T, int p), glp_ios_prev_node(T, p))
+// GUARDED(int, go_glp_ios_up_node, (gcall *c, glp_tree *T, int p), glp_ios_up_node(T, p))
+// GUARDED(int, go_glp_ios_node_level, (gcall *c, glp_tree *T, int p), glp_ios_node_level(T, p))
+// GUARDED(double, go_glp_ios_node_bound, (gcall *c, glp_tree *T, int p), glp_ios_node_bound(T, p))
+// GUARDED(int, go_glp_ios_add_row, (gcall *c, glp_tree *T, const char *name, int klass, int len, const int ind[], const double val[], int type, double rhs), glp_ios_add_row(T, name, klass, 0, len, ind, val, type, rhs))
+// GUARDED_VOID(go_glp_ios_del_row, (gcall *c, glp_tree *T, int i), glp_ios_del_row(T, i))
+// GUARDED_VOID(go_glp_ios_clear_pool, (gcall *c, glp_tree *T), glp_ios_clear_pool(T))
+// GUARDED(int, go_glp_ios_can_branch, (gcall *c, glp_tree *T, int j), glp_ios_can_branch(T, j))
+// GUARDED_VOID(go_glp_ios_branch_upon, (gcall *c, glp_tree *T, int j, int sel), glp_ios_branch_upon(T, j, sel))
+// GUARDED_VOID(go_glp_ios_select_node, (gcall *c, glp_tree *T, int p), glp_ios_select_node(T, p))
+// GUARDED(int, go_glp_ios_heur_sol, (gcall *c, glp_tree *T, const double x[]), glp_ios_heur_sol(T, x))
+import "C"
+
+// Reason for calling the branch-and-cut callback
+type Reason int
+
+const (
+	IROWGEN = Reason(C.GLP_IROWGEN) // request for row generation (lazy constraints)
+	IBINGO  = Reason(C.GLP_IBINGO)  // better integer solution found
+	IHEUR   = Reason(C.GLP_IHEUR)   // request for heuristic solution
+	ICUTGEN = Reason(C.GLP_ICUTGEN) // request for cut generation
+	IBRANCH = Reason(C.GLP_IBRANCH) // request for branching
+	ISELECT = Reason(C.GLP_ISELECT) // request for subproblem selection
+	IPREPRO = Reason(C.GLP_IPREPRO) // request for preprocessing
+)
+
+// Branch selection (see Tree.BranchUpon())
+type BranchSel int
+
+const (
+	NO_BRNCH = BranchSel(C.GLP_NO_BRNCH) // use general selection technique
+	DN_BRNCH = BranchSel(C.GLP_DN_BRNCH) // select down-branch
+	UP_BRNCH = BranchSel(C.GLP_UP_BRNCH) // select up-branch
+)
+
+// Tree represents the branch-and-bound tree of the MIP solver. It is
+// passed to the callback set with Iocp.SetCallback() and it is valid
+// only until the callback returns. Subproblems (nodes) of the tree
+// are referred to by their reference numbers.
+//
+// The callback is called on the goroutine (and thread) which called
+// Prob.Intopt(). If the callback panics the search is terminated and
+// the panic is raised again by Prob.Intopt(). If a GLPK routine called
+// by the callback fails with a fatal error the search is abandoned
+// (further calls of GLPK routines by the callback fail at once) and
+// Prob.Intopt() returns the *FatalError.
+type Tree struct {
+	t *C.glp_tree
+	s *iosState
+}
+
+func (t *Tree) check() {
+	if t.t == nil {
+		panic("Tree method called outside of the callback")
+	}
+}
+
+// call prepares a guarded call of a glp_ios_* routine.
+func (t *Tree) call() *gcall {
+	t.check()
+	return newCall(t.s.w)
+}
+
+// Reason returns the reason for calling the callback.
+func (t *Tree) Reason() Reason {
+	t.check()
+	return Reason(C.glp_ios_reason(t.t))
+}
+
+// GetProb returns the problem used by the MIP solver. It is the
+// problem Prob.Intopt() was called on unless the MIP presolver is used
+// in which case it is the presolved problem owned by the solver (which
+// becomes invalid, as if deleted, when the callback returns; Delete has
+// no effect on it). It may be inspected (e.g. with ColPrim() to obtain the
+// solution of the current LP relaxation) but it should not be changed
+// other than in the ways allowed by GLPK for the current reason.
+func (t *Tree) GetProb() *Prob {
+	t.check()
+	p := C.glp_ios_get_prob(t.t)
+	if p == t.s.prob.p.p {
+		return t.s.prob
+	}
+	if t.s.borrowed == nil || t.s.borrowed.p.p != p {
+		t.s.borrowed = &Prob{&prob{p: p, env: t.s.prob.p.env, w: t.s.w, borrowed: true}}
+	}
+	return t.s.borrowed
+}
+
+// TreeSize returns the number of active nodes, the number of all
+// nodes (active and inactive) currently in the tree, and the total
+// number of nodes created so far (including removed ones).
+func (t *Tree) TreeSize() (active, current, total int) {
+	t.check()
+	var a, n, c C.int
+	C.glp_ios_tree_size(t.t, &a, &n, &c)
+	return int(a), int(n), int(c)
+}
+
+// CurrNode returns the reference number of the current active
+// subproblem (or 0 if there is none).
+func (t *Tree) CurrNode() int {
+	t.check()
+	return int(C.glp_ios_curr_node(t.t))
+}
+
+// NextNode returns the reference number of the active subproblem
+// following subproblem p in the list of active subproblems (or the
+// first one if p is 0). It returns 0 if there is no such subproblem.
+func (t *Tree) NextNode(p int) int {
+	c := t.call()
+	r := int(C.go_glp_ios_next_node(&c.c, t.t, C.int(p)))
+	c.done()
+	return r
+}
+
+// PrevNode returns the reference number of the active subproblem
+// preceding subproblem p in the list of active subproblems (or the
+// last one if p is 0). It returns 0 if there is no such subproblem.
+func (t *Tree) PrevNode(p int) int {
+	c := t.call()
+	r := int(C.go_glp_ios_prev_node(&c.c, t.t, C.int(p)))
+	c.done()
+	return r
+}
+
+// UpNode returns the reference number of the parent of subproblem p
+// (or 0 if p is the root).
+func (t *Tree) UpNode(p int) int {
+	c := t.call()
+	r := int(C.go_glp_ios_up_node(&c.c, t.t, C.int(p)))
+	c.done()
+	return r
+}
+
+// NodeLevel returns the level of subproblem p in the tree (0 for the
+// root).
+func (t *Tree) NodeLevel(p int) int {
+	c := t.call()
+	r := int(C.go_glp_ios_node_level(&c.c, t.t, C.int(p)))
+	c.done()
+	return r
+}
+
+// NodeBound returns the local bound of subproblem p.
+func (t *Tree) NodeBound(p int) float64 {
+	c := t.call()
+	r := float64(C.go_glp_ios_node_bound(&c.c, t.t, C.int(p)))
+	c.done()
+	return r
+}
+
+// BestNode returns the reference number of the active subproblem
+// with the best local bound (or 0 if the tree is empty).
+func (t *Tree) BestNode() int {
+	t.check()
+	return int(C.glp_ios_best_node(t.t))
+}
+
+// MipGap returns the relative MIP gap (or +Inf if no integer feasible
+// solution has been found yet).
+func (t *Tree) MipGap() float64 {
+	t.check()
+	return unbounded(float64(C.glp_ios_mip_gap(t.t)))
+}
+
+// PoolSize returns the number of rows in the cut pool.
+func (t *Tree) PoolSize() int {
+	t.check()
+	return int(C.glp_ios_pool_size(t.t))
+}
+
+// AddRow adds a row (cutting plane or lazy constraint) to the cut
+// pool and returns its number in the pool. It may be called only for
+// reasons glpk.IROWGEN and glpk.ICUTGEN. The row is sum of val[k] *
+// x[ind[k]] for k = 1..len(ind)-1 (ind[0] and val[0] are unused, as in
+// Prob.SetMatRow()) with bounds type type_ (glpk.LO, glpk.UP or
+// glpk.FX) and right-hand side rhs. klass is 0 or a user-defined
+// class in range [101, 200]; name may be empty.
+func (t *Tree) AddRow(name string, klass int, ind []int32, val []float64, type_ BndsType, rhs float64) int {
+	if len(ind) != len(val) {
+		panic("len(ind) and len(val) should be equal")
+	}
+	var s *C.char
+	if name != "" {
+		s = C.CString(name)
+		defer C.free(unsafe.Pointer(s))
+	}
+	ind_ := (*reflect.SliceHeader)(unsafe.Pointer(&ind))
+	val_ := (*reflect.SliceHeader)(unsafe.Pointer(&val))
+	c := t.call()
+	r := int(C.go_glp_ios_add_row(&c.c, t.t, s, C.int(klass), C.int(len(ind)-1), (*C.int)(unsafe.Pointer(ind_.Data)), (*C.double)(unsafe.Pointer(val_.Data)), C.int(type_), C.double(rhs)))
+	c.done()
+	return r
+}
+
+// DelRow deletes i-th row from the cut pool.
+func (t *Tree) DelRow(i int) {
+	c := t.call()
+	C.go_glp_ios_del_row(&c.c, t.t, C.int(i))
+	c.done()
+}
+
+// ClearPool deletes all rows from the cut pool.
+func (t *Tree) ClearPool() {
+	c := t.call()
+	C.go_glp_ios_clear_pool(&c.c, t.t)
+	c.done()
+}
+
+// CanBranch returns true if it is possible to branch upon j-th
+// column, i.e., it is integer and has a fractional value in the
+// current LP relaxation.
+func (t *Tree) CanBranch(j int) bool {
+	c := t.call()
+	r := C.go_glp_ios_can_branch(&c.c, t.t, C.int(j))
+	c.done()
+	return r != 0
+}
+
+// BranchUpon chooses j-th column for branching (which should be
+// possible, see CanBranch()) and the branch to be solved next. It may
+// be called only for reason glpk.IBRANCH.
+func (t *Tree) BranchUpon(j int, sel BranchSel) {
+	c := t.call()
+	C.go_glp_ios_branch_upon(&c.c, t.t, C.int(j), C.int(sel))
+	c.done()
+}
+
+// SelectNode chooses active subproblem p to be solved next. It may
+// be called only for reason glpk.ISELECT.
+func (t *Tree) SelectNode(p int) {
+	c := t.call()
+	C.go_glp_ios_select_node(&c.c, t.t, C.int(p))
+	c.done()
+}
+
+// HeurSol provides an integer feasible solution found by a heuristic
+// where x[j] is the value of j-th column (x[0] is unused). It returns
+// true if the solution was accepted (i.e., it is better than the best
+// known one).
+func (t *Tree) HeurSol(x []float64) bool {
+	t.check()
+	if len(x) != t.GetProb().NumCols()+1 {
+		panic("len(x) should be equal to the number of columns plus 1")
+	}
+	x_ := (*reflect.SliceHeader)(unsafe.Pointer(&x))
+	c := t.call()
+	r := C.go_glp_ios_heur_sol(&c.c, t.t, (*C.double)(unsafe.Pointer(x_.Data)))
+	c.done()
+	return r == 0
+}
+
+// Terminate terminates the search. Prob.Intopt() then returns
+// glpk.ESTOP.
+func (t *Tree) Terminate() {
+	t.check()
+	C.glp_ios_terminate(t.t)
+}
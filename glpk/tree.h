@@ -0,0 +1,28 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+#ifndef GO_GLPK_TREE_H
+#define GO_GLPK_TREE_H
+
+#include <stdint.h>
+#include <glpk.h>
+
+// iocp_set_cb sets the branch-and-cut callback of parm so that it
+// calls the Go callback with handle h.
+void iocp_set_cb(glp_iocp *parm, uintptr_t h);
+
+#endif
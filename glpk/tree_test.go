@@ -0,0 +1,254 @@
+// This code is part of glpk package (Go bindings for the GNU Linear Programming Kit).
+//
+// Copyright (C) 2014 Łukasz Pankowski <lukpank@o2.pl>
+//
+// Package glpk is free software: you can redistribute it and/or
+// modify it under the terms of the GNU General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// Package glpk is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU
+// General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with glpk package. If not, see <http://www.gnu.org/licenses/>.
+
+package glpk
+
+import (
+	"io/ioutil"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// pairProb returns the problem: maximize x1 + x2 subject to x1 + x2
+// <= 2 for binary x1 and x2 with the optimal solution of its LP
+// relaxation (the lazy constraint x1 + x2 <= 1 is added by lazyPair).
+func pairProb() *Prob {
+	lp := New()
+	lp.SetTermWriter(ioutil.Discard)
+	lp.SetObjDir(MAX)
+	lp.AddRows(1)
+	lp.SetRowBnds(1, UP, 0, 2)
+	lp.AddCols(2)
+	for j := 1; j <= 2; j++ {
+		lp.SetColKind(j, BV)
+		lp.SetObjCoef(j, 1)
+	}
+	lp.SetMatRow(1, []int32{0, 1, 2}, []float64{0, 1, 1})
+	if err := lp.Simplex(nil); err != nil {
+		panic(err)
+	}
+	return lp
+}
+
+// lazyPair adds the lazy constraint x1 + x2 <= 1 when it is violated.
+func lazyPair(t *Tree) {
+	if t.Reason() != IROWGEN {
+		return
+	}
+	p := t.GetProb()
+	if p.ColPrim(1)+p.ColPrim(2) > 1+1e-6 {
+		t.AddRow("lazy", 0, []int32{0, 1, 2}, []float64{0, 1, 1}, UP, 1)
+	}
+}
+
+func TestCallbackLazy(t *testing.T) {
+	lp := pairProb()
+	defer lp.Delete()
+	iocp := NewIocp()
+	reasons := map[Reason]int{}
+	iocp.SetCallback(func(tree *Tree) {
+		reasons[tree.Reason()]++
+		if tree.Reason() == ISELECT {
+			if p := tree.BestNode(); p != 0 {
+				tree.SelectNode(p)
+			}
+		}
+		lazyPair(tree)
+	})
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Intopt error: %v", err)
+	}
+	CheckClose(t, lp.MipObjVal(), 1)
+	if reasons[IROWGEN] == 0 {
+		t.Errorf("callback not called with IROWGEN: %v", reasons)
+	}
+}
+
+func TestCallbackTerminate(t *testing.T) {
+	lp := pairProb()
+	defer lp.Delete()
+	iocp := NewIocp()
+	var saved *Tree
+	iocp.SetCallback(func(tree *Tree) {
+		saved = tree
+		if active, _, _ := tree.TreeSize(); active < 1 {
+			t.Errorf("expected active nodes but got %d", active)
+		}
+		tree.Terminate()
+	})
+	if err := lp.Intopt(iocp); err != ESTOP {
+		t.Errorf("expected ESTOP but got %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic when using Tree after the callback")
+		}
+	}()
+	saved.Reason()
+}
+
+func TestCallbackPresolvedProb(t *testing.T) {
+	// maximize 5x1 + 4x2 + 3x3 subject to 2x1 + 3x2 + x3 <= 5,
+	// 4x1 + x2 + 2x3 <= 11 and 3x1 + 4x2 + 2x3 <= 8 for nonnegative
+	// integer x1, x2 and x3 (not solved by the presolver alone)
+	lp := New()
+	defer lp.Delete()
+	lp.SetTermWriter(ioutil.Discard)
+	lp.SetObjDir(MAX)
+	lp.AddRows(3)
+	lp.AddCols(3)
+	for j, c := range []float64{5, 4, 3} {
+		lp.SetColBnds(j+1, LO, 0, 0)
+		lp.SetColKind(j+1, IV)
+		lp.SetObjCoef(j+1, c)
+	}
+	for i, r := range [][]float64{{0, 2, 3, 1, 5}, {0, 4, 1, 2, 11}, {0, 3, 4, 2, 8}} {
+		lp.SetRowBnds(i+1, UP, 0, r[4])
+		lp.SetMatRow(i+1, []int32{0, 1, 2, 3}, r[:4])
+	}
+	iocp := NewIocp()
+	iocp.SetPresolve(true)
+	var saved *Prob
+	iocp.SetCallback(func(tree *Tree) {
+		p := tree.GetProb()
+		if p == lp {
+			t.Errorf("expected the presolved problem")
+		}
+		p.NumCols()
+		saved = p
+	})
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Intopt error: %v", err)
+	}
+	if saved == nil {
+		t.Fatalf("callback not called")
+	}
+	saved.Delete() // no effect on a problem owned by the solver
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic when using the presolved problem after the callback")
+		}
+	}()
+	saved.NumCols()
+}
+
+func TestCallbackDelete(t *testing.T) {
+	lp := pairProb()
+	iocp := NewIocp()
+	iocp.SetCallback(func(tree *Tree) {
+		if fatal(lp.Delete) == nil {
+			t.Errorf("expected panic when deleting the problem during Intopt")
+		}
+		if fatal(lp.Erase) == nil {
+			t.Errorf("expected panic when erasing the problem during Intopt")
+		}
+	})
+	if err := lp.Intopt(iocp); err != nil {
+		t.Fatalf("Intopt error: %v", err)
+	}
+	CheckClose(t, lp.MipObjVal(), 2)
+	lp.Delete()
+}
+
+func TestCallbackFatalError(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	for _, recovered := range []bool{false, true} {
+		lp := pairProb()
+		iocp := NewIocp()
+		calls := 0
+		iocp.SetCallback(func(tree *Tree) {
+			calls++
+			if !recovered {
+				tree.DelRow(100) // invalid
+			}
+			if _, ok := fatal(func() { tree.DelRow(100) }).(*FatalError); !ok {
+				t.Errorf("expected *FatalError panic")
+			}
+			// GLPK routines fail until the solver is left
+			if _, ok := fatal(func() { tree.ClearPool() }).(*FatalError); !ok {
+				t.Errorf("expected *FatalError panic after a fatal error")
+			}
+		})
+		if _, ok := lp.Intopt(iocp).(*FatalError); !ok {
+			t.Errorf("expected *FatalError")
+		}
+		if calls != 1 {
+			t.Errorf("callback called %d times after a fatal error", calls)
+		}
+		if !lp.p.invalid() {
+			t.Errorf("problem not invalidated by a fatal error")
+		}
+	}
+
+	// GLPK is still usable
+	lp := pairProb()
+	defer lp.Delete()
+	if err := lp.Intopt(nil); err != nil {
+		t.Fatalf("Intopt error: %v", err)
+	}
+	CheckClose(t, lp.MipObjVal(), 2)
+}
+
+func TestCallbackPanic(t *testing.T) {
+	lp := pairProb()
+	defer lp.Delete()
+	iocp := NewIocp()
+	iocp.SetCallback(func(tree *Tree) {
+		panic("callback")
+	})
+	defer func() {
+		if v := recover(); v != "callback" {
+			t.Errorf("expected panic \"callback\" but got %#v", v)
+		}
+	}()
+	lp.Intopt(iocp)
+	t.Errorf("Intopt did not panic")
+}
+
+func TestCallbackConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(lazy bool) {
+			defer wg.Done()
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+			lp := pairProb()
+			defer lp.Delete()
+			parm := NewIocp()
+			if lazy {
+				parm.SetCallback(lazyPair)
+			} else {
+				parm.SetCallback(func(tree *Tree) {})
+			}
+			if err := lp.Intopt(parm); err != nil {
+				t.Errorf("Intopt error: %v", err)
+				return
+			}
+			want := 2.0
+			if lazy {
+				want = 1
+			}
+			if v := lp.MipObjVal(); v != want {
+				t.Errorf("expected %g but got %g", want, v)
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+}